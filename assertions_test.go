@@ -12,22 +12,9 @@ import (
 
 var errTest = errors.New("test error")
 
-type StackTracer interface {
-	StackTrace() errors.StackTrace
-}
-
 func assertSingleStack(t *testing.T, err error) {
 	t.Helper()
-
-	count := 0
-	for e := err; e != nil; e = errors.Unwrap(e) {
-		if _, ok := e.(StackTracer); ok {
-			count++
-		}
-	}
-	if count != 1 {
-		t.Errorf("expected %#v to have exactly one stack in chain, got %d", err, count)
-	}
+	errorstest.AssertSingleStack(t, err)
 }
 
 func assertFormatRegexp(t *testing.T, arg interface{}, format, want string) {
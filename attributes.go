@@ -0,0 +1,152 @@
+package errors
+
+import (
+	"bytes"
+	"runtime"
+	"strconv"
+	"sync/atomic"
+	"time"
+)
+
+const budgetRemainingKey = "budgetRemaining"
+
+// WithBudget records how much time of a timeout budget was left when the error occurred.
+// The value is set as a duration field "budgetRemaining". For a deadline error it shows
+// whether the budget was exhausted or the operation failed early.
+func WithBudget(remaining time.Duration) Option {
+	return Marker(DurationField{Key: budgetRemainingKey, Value: remaining})
+}
+
+// GetBudget returns the remaining time budget set by WithBudget option.
+// If there are several budgets in the chain, then the outermost one is returned.
+func GetBudget(err error) (time.Duration, bool) {
+	value, _ := GetField(err, budgetRemainingKey)
+	budget, ok := value.(time.Duration)
+
+	return budget, ok
+}
+
+const backoffHistoryKey = "backoffHistory"
+
+// WithBackoffHistory attaches the sequence of backoff delays used before giving up
+// as a durations field "backoffHistory". It helps to tune retry policies from logs.
+func WithBackoffHistory(delays []time.Duration) Option {
+	return Marker(DurationsField{Key: backoffHistoryKey, Values: delays})
+}
+
+// GetBackoffHistory returns the backoff delays set by WithBackoffHistory option.
+// If there are several histories in the chain, then the outermost one is returned.
+func GetBackoffHistory(err error) ([]time.Duration, bool) {
+	value, _ := GetField(err, backoffHistoryKey)
+	delays, ok := value.([]time.Duration)
+
+	return delays, ok
+}
+
+const retryableKey = "retryable"
+
+// Retryable marks an error as worth retrying. The mark is set as a bool field "retryable",
+// so it is visible in logs and survives wrapping by Wrap and Errorf.
+func Retryable() Option {
+	return Marker(BoolField{Key: retryableKey, Value: true})
+}
+
+// IsRetryable reports whether the error was marked by Retryable option.
+func IsRetryable(err error) bool {
+	value, _ := GetField(err, retryableKey)
+	retryable, _ := value.(bool)
+
+	return retryable
+}
+
+const httpStatusKey = "httpStatus"
+
+// WithHTTPStatus sets HTTP status code to be used in a response for the error.
+// The status is set as an int field "httpStatus", so it is visible in logs and JSON.
+func WithHTTPStatus(code int) Option {
+	return Marker(IntField{Key: httpStatusKey, Value: code})
+}
+
+// HTTPStatus returns HTTP status code set by WithHTTPStatus option and reports whether it was found.
+// If there are several statuses in the chain, then the outermost one is returned.
+func HTTPStatus(err error) (int, bool) {
+	value, _ := GetField(err, httpStatusKey)
+	code, ok := value.(int)
+
+	return code, ok
+}
+
+const codeKey = "code"
+
+// WithCode sets a machine-readable code of the error, that can be used to route errors
+// independently of sentinel errors. The code is set as a string field "code".
+func WithCode(code string) Option {
+	return Marker(StringField{Key: codeKey, Value: code})
+}
+
+// GetCode returns the code set by WithCode option and reports whether it was found.
+// If there are several codes in the chain, then the outermost one wins.
+func GetCode(err error) (string, bool) {
+	value, _ := GetField(err, codeKey)
+	code, ok := value.(string)
+
+	return code, ok
+}
+
+const goroutineIDKey = "goroutineID"
+
+// GoroutineID records the numeric ID of the goroutine the error was created on
+// as a uint field "goroutineID". It helps to debug concurrency issues.
+//
+// The ID is parsed from the header of the runtime.Stack output, that is relatively
+// expensive, so the option should be used only when the ID is really needed.
+func GoroutineID() Option {
+	return func(options *Options) {
+		options.AddField(MarkerField{Field: UintField{Key: goroutineIDKey, Value: goroutineID()}})
+	}
+}
+
+// goroutineID parses the ID of the current goroutine from the "goroutine 123 [running]:" header.
+func goroutineID() uint {
+	var buffer [64]byte
+	header := buffer[:runtime.Stack(buffer[:], false)]
+	header = bytes.TrimPrefix(header, []byte("goroutine "))
+	if i := bytes.IndexByte(header, ' '); i >= 0 {
+		header = header[:i]
+	}
+	id, _ := strconv.ParseUint(string(header), 10, 64)
+
+	return uint(id)
+}
+
+const timestampKey = "timestamp"
+
+var clock atomic.Value
+
+func init() {
+	clock.Store(time.Now)
+}
+
+// SetClock sets the function used by Timestamp option to get the current time.
+// It is made to inject a fake clock in tests. Passing nil restores time.Now.
+// It is safe to call SetClock concurrently with creating errors.
+func SetClock(now func() time.Time) {
+	if now == nil {
+		now = time.Now
+	}
+	clock.Store(now)
+}
+
+// Timestamp records the time when the error was created as a time field "timestamp".
+func Timestamp() Option {
+	return Marker(TimeField{Key: timestampKey, Value: clock.Load().(func() time.Time)()})
+}
+
+// CreatedAt returns the time set by Timestamp option.
+// If there are several timestamps in the chain, then the outermost one is returned.
+func CreatedAt(err error) (time.Time, bool) {
+	value, _ := GetField(err, timestampKey)
+	timestamp, ok := value.(time.Time)
+
+	return timestamp, ok
+}
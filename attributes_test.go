@@ -0,0 +1,291 @@
+package errors_test
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/muonsoft/errors"
+	"github.com/muonsoft/errors/errorstest"
+)
+
+func TestWithBudget(t *testing.T) {
+	err := errors.Wrap(
+		errors.Errorf("deadline exceeded", errors.WithBudget(150*time.Millisecond)),
+		errors.String("key", "value"),
+	)
+
+	budget, ok := errors.GetBudget(err)
+	if !ok {
+		t.Fatal("want error to have a budget")
+	}
+	if budget != 150*time.Millisecond {
+		t.Errorf("want budget %s, got %s", 150*time.Millisecond, budget)
+	}
+	logger := errorstest.NewLogger()
+	errors.Log(err, logger)
+	logger.AssertField(t, "budgetRemaining", 150*time.Millisecond)
+}
+
+func TestWithBudget_MarshalJSON(t *testing.T) {
+	err := errors.Errorf("deadline exceeded", errors.WithBudget(time.Second))
+
+	jsonData, e := json.Marshal(err)
+	if e != nil {
+		t.Fatalf("expected %#v to be marshalable into json: %v", err, e)
+	}
+	var jsonError struct {
+		BudgetRemaining time.Duration `json:"budgetRemaining"`
+	}
+	e = json.Unmarshal(jsonData, &jsonError)
+	if e != nil {
+		t.Fatalf("failed to unmarshal json: %v", e)
+	}
+
+	if jsonError.BudgetRemaining != time.Second {
+		t.Errorf(`want "budgetRemaining" to be %s, got %s`, time.Second, jsonError.BudgetRemaining)
+	}
+}
+
+func TestGetBudget_noBudget(t *testing.T) {
+	_, ok := errors.GetBudget(errors.Errorf("ooh", errors.Duration("elapsed", time.Second)))
+	if ok {
+		t.Error("want error to have no budget")
+	}
+}
+
+func TestWithBackoffHistory(t *testing.T) {
+	delays := []time.Duration{100 * time.Millisecond, 200 * time.Millisecond, 400 * time.Millisecond}
+	err := errors.Wrap(errors.Errorf("retries exhausted", errors.WithBackoffHistory(delays)))
+
+	got, ok := errors.GetBackoffHistory(err)
+	if !ok {
+		t.Fatal("want error to have a backoff history")
+	}
+	if !reflect.DeepEqual(got, delays) {
+		t.Errorf("want backoff history %v, got %v", delays, got)
+	}
+	logger := errorstest.NewLogger()
+	errors.Log(err, logger)
+	logger.AssertField(t, "backoffHistory", delays)
+
+	jsonData, e := json.Marshal(err)
+	if e != nil {
+		t.Fatalf("expected %#v to be marshalable into json: %v", err, e)
+	}
+	var jsonError struct {
+		BackoffHistory []time.Duration `json:"backoffHistory"`
+	}
+	e = json.Unmarshal(jsonData, &jsonError)
+	if e != nil {
+		t.Fatalf("failed to unmarshal json: %v", e)
+	}
+	if !reflect.DeepEqual(jsonError.BackoffHistory, delays) {
+		t.Errorf(`want "backoffHistory" to be %v, got %v`, delays, jsonError.BackoffHistory)
+	}
+}
+
+func TestGetBackoffHistory_noHistory(t *testing.T) {
+	_, ok := errors.GetBackoffHistory(errors.Errorf("ooh"))
+	if ok {
+		t.Error("want error to have no backoff history")
+	}
+}
+
+func TestRetryable(t *testing.T) {
+	err := errors.Wrap(
+		errors.Errorf("find product: %w", errors.Errorf("connection refused", errors.Retryable())),
+		errors.String("key", "value"),
+	)
+
+	if !errors.IsRetryable(err) {
+		t.Errorf("want %#v to be retryable", err)
+	}
+	jsonData, e := json.Marshal(err)
+	if e != nil {
+		t.Fatalf("expected %#v to be marshalable into json: %v", err, e)
+	}
+	var jsonError struct {
+		Retryable bool `json:"retryable"`
+	}
+	e = json.Unmarshal(jsonData, &jsonError)
+	if e != nil {
+		t.Fatalf("failed to unmarshal json: %v", e)
+	}
+	if !jsonError.Retryable {
+		t.Errorf(`want "retryable" to be true in %s`, jsonData)
+	}
+}
+
+func TestIsRetryable_notRetryable(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+	}{
+		{name: "nil", err: nil},
+		{name: "sentinel error", err: errTest},
+		{name: "wrapped error", err: errors.Wrap(errors.Errorf("ooh", errors.String("key", "value")))},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if errors.IsRetryable(test.err) {
+				t.Errorf("want %#v not to be retryable", test.err)
+			}
+		})
+	}
+}
+
+func TestHTTPStatus(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		wantStatus int
+		wantFound  bool
+	}{
+		{
+			name:       "error with status",
+			err:        errors.Errorf("not found", errors.WithHTTPStatus(404)),
+			wantStatus: 404,
+			wantFound:  true,
+		},
+		{
+			name: "wrapped error with status",
+			err: errors.Wrap(
+				errors.Errorf("find product: %w", errors.Errorf("not found", errors.WithHTTPStatus(404))),
+				errors.String("key", "value"),
+			),
+			wantStatus: 404,
+			wantFound:  true,
+		},
+		{
+			name:       "error without status",
+			err:        errors.Errorf("ooh"),
+			wantStatus: 0,
+			wantFound:  false,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			status, found := errors.HTTPStatus(test.err)
+			if found != test.wantFound {
+				t.Errorf("want found to be %t, got %t", test.wantFound, found)
+			}
+			if status != test.wantStatus {
+				t.Errorf("want status %d, got %d", test.wantStatus, status)
+			}
+		})
+	}
+}
+
+func TestWithHTTPStatus_logging(t *testing.T) {
+	err := errors.Errorf("not found", errors.WithHTTPStatus(404))
+
+	logger := errorstest.NewLogger()
+	errors.Log(err, logger)
+	logger.AssertField(t, "httpStatus", 404)
+
+	jsonData, e := json.Marshal(err)
+	if e != nil {
+		t.Fatalf("expected %#v to be marshalable into json: %v", err, e)
+	}
+	var jsonError struct {
+		HTTPStatus int `json:"httpStatus"`
+	}
+	e = json.Unmarshal(jsonData, &jsonError)
+	if e != nil {
+		t.Fatalf("failed to unmarshal json: %v", e)
+	}
+	if jsonError.HTTPStatus != 404 {
+		t.Errorf(`want "httpStatus" to be 404 in %s`, jsonData)
+	}
+}
+
+func TestGetCode(t *testing.T) {
+	err := errors.Wrap(
+		errors.Errorf("find product: %w", errors.Errorf("not found", errors.WithCode("productNotFound"))),
+		errors.WithCode("catalogUnavailable"),
+	)
+
+	code, ok := errors.GetCode(err)
+	if !ok {
+		t.Fatalf("want %#v to have a code", err)
+	}
+	if code != "catalogUnavailable" {
+		t.Errorf(`want the outermost code "catalogUnavailable", got %q`, code)
+	}
+	if _, ok := errors.GetCode(errors.Errorf("ooh")); ok {
+		t.Error("want error without a code")
+	}
+}
+
+func TestWithCode_MarshalJSON(t *testing.T) {
+	err := errors.Wrap(errors.Errorf("not found", errors.WithCode("productNotFound")))
+
+	jsonData, e := json.Marshal(err)
+	if e != nil {
+		t.Fatalf("expected %#v to be marshalable into json: %v", err, e)
+	}
+	var jsonError struct {
+		Code string `json:"code"`
+	}
+	e = json.Unmarshal(jsonData, &jsonError)
+	if e != nil {
+		t.Fatalf("failed to unmarshal json: %v", e)
+	}
+	if jsonError.Code != "productNotFound" {
+		t.Errorf(`want "code" to be "productNotFound" in %s`, jsonData)
+	}
+}
+
+func TestGoroutineID(t *testing.T) {
+	results := make(chan error, 2)
+	for i := 0; i < 2; i++ {
+		go func() {
+			results <- errors.Errorf("ooh", errors.GoroutineID())
+		}()
+	}
+
+	first, _ := errors.GetField(<-results, "goroutineID")
+	second, _ := errors.GetField(<-results, "goroutineID")
+
+	firstID, ok := first.(uint)
+	if !ok || firstID == 0 {
+		t.Fatalf("want goroutine ID to be a positive uint, got %#v", first)
+	}
+	secondID, ok := second.(uint)
+	if !ok || secondID == 0 {
+		t.Fatalf("want goroutine ID to be a positive uint, got %#v", second)
+	}
+	if firstID == secondID {
+		t.Errorf("want errors created on different goroutines to have different IDs, got %d", firstID)
+	}
+}
+
+func TestTimestamp(t *testing.T) {
+	defer errors.SetClock(nil)
+	inner := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	outer := inner.Add(time.Second)
+
+	errors.SetClock(func() time.Time { return inner })
+	err := errors.Errorf("ooh", errors.Timestamp())
+	errors.SetClock(func() time.Time { return outer })
+	err = errors.Wrap(err, errors.Timestamp())
+
+	createdAt, ok := errors.CreatedAt(err)
+	if !ok {
+		t.Fatal("want error to have a timestamp")
+	}
+	if !createdAt.Equal(outer) {
+		t.Errorf("want outermost timestamp %v, got %v", outer, createdAt)
+	}
+	logger := errorstest.NewLogger()
+	errors.Log(err, logger)
+	logger.AssertField(t, "timestamp", inner)
+}
+
+func TestCreatedAt_noTimestamp(t *testing.T) {
+	if _, ok := errors.CreatedAt(errors.Errorf("ooh")); ok {
+		t.Error("want no timestamp")
+	}
+}
@@ -0,0 +1,113 @@
+package errors_test
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/muonsoft/errors"
+	"github.com/muonsoft/errors/errorstest"
+)
+
+func BenchmarkWrap(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = errors.Wrap(errTest)
+	}
+}
+
+func BenchmarkWrap_parallel(b *testing.B) {
+	b.ReportAllocs()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			_ = errors.Wrap(errTest)
+		}
+	})
+}
+
+func BenchmarkErrorf(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = errors.Errorf("ooh: %w", errTest, errors.String("key", "value"))
+	}
+}
+
+func BenchmarkLog(b *testing.B) {
+	err := errors.Wrap(errTest, errors.String("key", "value"))
+	logger := errorstest.NewLogger()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		errors.Log(err, logger)
+	}
+}
+
+func BenchmarkFormat(b *testing.B) {
+	err := errors.Wrap(errTest, errors.String("key", "value"))
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = fmt.Sprintf("%+v", err)
+	}
+}
+
+func BenchmarkMarshalJSON(b *testing.B) {
+	err := errors.Wrap(errTest, errors.String("key", "value"))
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _ = err.(interface{ MarshalJSON() ([]byte, error) }).MarshalJSON()
+	}
+}
+
+func BenchmarkLogThenMarshalJSON(b *testing.B) {
+	err := errors.Wrap(errTest, errors.String("key", "value"))
+	logger := errorstest.NewLogger()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		errors.Log(err, logger)
+		_, _ = err.(interface{ MarshalJSON() ([]byte, error) }).MarshalJSON()
+	}
+}
+
+func TestStackTrace_cachedConcurrently(t *testing.T) {
+	err := errors.Wrap(errTest)
+	traces := make([]errors.StackTrace, 8)
+
+	var wg sync.WaitGroup
+	for i := range traces {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			traces[i], _ = errors.GetStackTrace(err)
+		}(i)
+	}
+	wg.Wait()
+
+	for _, trace := range traces[1:] {
+		if len(trace) == 0 || &trace[0] != &traces[0][0] {
+			t.Fatal("want stack trace to be resolved once and shared by all consumers")
+		}
+	}
+}
+
+func TestStackTrace_repeatedFormat(t *testing.T) {
+	err := errors.Wrap(errors.New("ooh"), errors.String("key", "value"))
+
+	first := fmt.Sprintf("%+v", err)
+	second := fmt.Sprintf("%+v", err)
+
+	if first != second {
+		t.Errorf("want repeated format to be identical:\nfirst: %q\nsecond: %q", first, second)
+	}
+	trace := stackTraceOf(t, err)
+	if len(trace) == 0 || !strings.Contains(first, trace[0].Name()) {
+		t.Errorf("want formatted error to contain stack trace %v, got %q", trace.Strings(), first)
+	}
+}
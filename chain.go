@@ -0,0 +1,490 @@
+package errors
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// MaxUnwrapDepth limits the number of errors visited while walking err's chain by As, Log, Fields,
+// marshaling into JSON and other functions of this package. It protects against malformed errors
+// whose Unwrap method returns the error itself or forms a cycle: walking is stopped at this depth
+// instead of looping forever. Branches of joined errors continue the depth of the joined error.
+// Branches of the same joined error are walked once and the total number of visited errors
+// is limited too, so joined errors returning themselves as several branches do not multiply
+// the work exponentially.
+// Note that Is is an alias to the standard function, so it is not protected.
+const MaxUnwrapDepth = 100
+
+// maxUnwrapVisits limits the total number of errors visited during a single walk of err's chain.
+const maxUnwrapVisits = 10000
+
+// unwrapWalk tracks a single walk of err's chain, which is shared by all its branches.
+type unwrapWalk struct {
+	visits int
+	joins  map[error]bool
+}
+
+// next reports whether an error at the depth can be visited and counts the visit.
+func (w *unwrapWalk) next(depth int) bool {
+	if depth >= MaxUnwrapDepth || w.visits >= maxUnwrapVisits {
+		return false
+	}
+	w.visits++
+
+	return true
+}
+
+// enter reports whether branches of the joined error should be walked. Branches of the same
+// joined error are walked only once, so a joined error returning itself as a branch is not expanded.
+func (w *unwrapWalk) enter(joined error) bool {
+	if !reflect.TypeOf(joined).Comparable() {
+		return true
+	}
+	if w.joins[joined] {
+		return false
+	}
+	if w.joins == nil {
+		w.joins = make(map[error]bool)
+	}
+	w.joins[joined] = true
+
+	return true
+}
+
+// DiagnoseChain inspects err's chain and returns warnings about layers that may break
+// the features of this package: errors from other packages wrapping errors of this package
+// (for example, created by fmt.Errorf instead of errors.Errorf) and errors with a second stack trace.
+// It is made for debugging and migration purposes and returns nil for a healthy chain.
+//
+// Layers are numbered from 1 starting with err itself. An error created internally by
+// errors.Errorf is considered to be a part of the same layer. The chain is walked
+// by repeatedly calling Unwrap, so branches of joined errors are not inspected.
+func DiagnoseChain(err error) []string {
+	var warnings []string
+
+	layer := 0
+	isUnderWrapper := false
+	stackFound := false
+	for e, depth := err, 0; e != nil && depth < MaxUnwrapDepth; e, depth = Unwrap(e), depth+1 {
+		isOwn := isOwnWrapper(e)
+		if isOwn || !isUnderWrapper {
+			layer++
+		}
+		if !isOwn && !isUnderWrapper && isWrapper(Unwrap(e)) {
+			warnings = append(warnings, fmt.Sprintf(
+				"layer %d (%T) is not a wrapper; fields may be unreachable", layer, e,
+			))
+		}
+		if _, ok := e.(StackTracer); ok {
+			if stackFound {
+				warnings = append(warnings, fmt.Sprintf(
+					"layer %d (%T) has a second stack trace", layer, e,
+				))
+			}
+			stackFound = true
+		}
+		isUnderWrapper = isOwn
+	}
+
+	return warnings
+}
+
+// isOwnWrapper reports whether err is a wrapping error of this package.
+func isOwnWrapper(err error) bool {
+	switch err.(type) {
+	case wrapper, *temporaryError, *unstacked, *unstackedJoin:
+		return true
+	}
+	return false
+}
+
+// Breadcrumbs returns an ordered list of messages that were added at each layer of err's chain,
+// starting from the outermost one. For every error in the chain, the message of the wrapped error
+// is cut from the end (or the beginning) of its own message with the separator (": " or " ")
+// on that side. Layers that do not add any text (for example, errors.Wrap) are skipped.
+// The last element is the message of the innermost error.
+//
+// For example, for the error errors.Errorf("find product: %w", errors.Errorf("query: %w", sql.ErrNoRows))
+// it returns []string{"find product", "query", "sql: no rows in result set"}.
+//
+// The chain is walked by repeatedly calling Unwrap, so joined errors are considered as the last layer.
+func Breadcrumbs(err error) []string {
+	var breadcrumbs []string
+
+	for e, depth := err, 0; e != nil && depth < MaxUnwrapDepth; e, depth = Unwrap(e), depth+1 {
+		message := e.Error()
+		if inner := Unwrap(e); inner != nil {
+			innerMessage := inner.Error()
+			if message == innerMessage {
+				continue
+			}
+			message = cutInnerMessage(message, innerMessage)
+		}
+		if message != "" {
+			breadcrumbs = append(breadcrumbs, message)
+		}
+	}
+
+	return breadcrumbs
+}
+
+// cutInnerMessage cuts the inner message with a separator from the end or the beginning of the message.
+// If the message does not start or end with the inner message, it is returned as is.
+func cutInnerMessage(message, innerMessage string) string {
+	if m, ok := strings.CutSuffix(message, innerMessage); ok {
+		if m, ok := strings.CutSuffix(m, ": "); ok {
+			return m
+		}
+		return strings.TrimSuffix(m, " ")
+	}
+	if m, ok := strings.CutPrefix(message, innerMessage); ok {
+		if m, ok := strings.CutPrefix(m, ": "); ok {
+			return m
+		}
+		return strings.TrimPrefix(m, " ")
+	}
+	return message
+}
+
+// TypePath returns a string describing the structural shape of err's chain: names of concrete
+// error types joined by ">". Package names are omitted, so the result does not depend on messages
+// or lines of code and can be used to group errors. For example, for errors.Wrap(&ForbiddenError{})
+// it returns "*stacked>*ForbiddenError".
+//
+// Branches of joined errors are listed in parentheses and separated by commas,
+// for example "*stacked>*joinError(*errorString,*stacked>*ForbiddenError)".
+func TypePath(err error) string {
+	var path strings.Builder
+	writeTypePath(&path, err, 0, &unwrapWalk{})
+
+	return path.String()
+}
+
+func writeTypePath(path *strings.Builder, err error, depth int, walk *unwrapWalk) {
+	i := 0
+	for e := err; e != nil && walk.next(depth); e, depth = Unwrap(e), depth+1 {
+		if i > 0 {
+			path.WriteString(">")
+		}
+		i++
+		path.WriteString(typeName(e))
+		if joined, ok := e.(interface{ Unwrap() []error }); ok && walk.enter(e) {
+			path.WriteString("(")
+			for j, branch := range joined.Unwrap() {
+				if j > 0 {
+					path.WriteString(",")
+				}
+				writeTypePath(path, branch, depth+1, walk)
+			}
+			path.WriteString(")")
+		}
+	}
+}
+
+// typeName returns the name of err's type without a package name.
+func typeName(err error) string {
+	name := fmt.Sprintf("%T", err)
+	pointers := len(name) - len(strings.TrimLeft(name, "*"))
+
+	return name[:pointers] + name[strings.LastIndex(name, ".")+1:]
+}
+
+// Chain returns err and every error in its chain obtained by repeatedly calling Unwrap.
+// Joined errors (with Unwrap() []error method) are flattened in depth-first order:
+// the joined error itself goes first, then the whole chain of its first branch,
+// then the chain of the second branch and so on. It returns nil for a nil error.
+func Chain(err error) []error {
+	var chain []error
+
+	walkChain(err, func(e error) bool {
+		chain = append(chain, e)
+		return true
+	})
+
+	return chain
+}
+
+// Root returns the deepest cause of err: the first error found in depth-first order
+// that does not wrap any other error. For joined errors, it returns the root of the first branch.
+// It returns nil for a nil error.
+func Root(err error) error {
+	for depth := 0; err != nil && depth < MaxUnwrapDepth; depth++ {
+		switch x := err.(type) {
+		case interface{ Unwrap() error }:
+			inner := x.Unwrap()
+			if inner == nil {
+				return err
+			}
+			err = inner
+		case interface{ Unwrap() []error }:
+			errs := x.Unwrap()
+			if len(errs) == 0 {
+				return err
+			}
+			err = errs[0]
+		default:
+			return err
+		}
+	}
+
+	return err
+}
+
+// Cause returns the underlying cause of err, if possible. It is made for compatibility
+// with github.com/pkg/errors: it repeatedly unwraps errors implementing the legacy
+// Cause() error method or the standard Unwrap() error method until it reaches
+// an error that implements neither of them.
+//
+// Unlike Root, Cause does not walk into branches of joined errors: such an error is
+// returned as the cause.
+func Cause(err error) error {
+	for depth := 0; err != nil && depth < MaxUnwrapDepth; depth++ {
+		var cause error
+		switch x := err.(type) {
+		case interface{ Cause() error }:
+			cause = x.Cause()
+		case interface{ Unwrap() error }:
+			cause = x.Unwrap()
+		}
+		if cause == nil {
+			return err
+		}
+		err = cause
+	}
+
+	return err
+}
+
+// Find returns the first error in err's chain that satisfies the match predicate and true,
+// or nil and false if there is no such error. Errors are checked in the order of Chain function:
+// from the outermost error to the innermost one, branches of joined errors in depth-first order.
+// It complements Is and As for ad-hoc conditions, for example, matching by a field value.
+func Find(err error, match func(err error) bool) (error, bool) {
+	var found error
+
+	walkChain(err, func(e error) bool {
+		if match(e) {
+			found = e
+			return false
+		}
+		return true
+	})
+
+	return found, found != nil
+}
+
+// walkChain calls visit for err and every error in its chain obtained by repeatedly
+// calling Unwrap. Branches of joined errors (with Unwrap() []error method) are walked
+// in depth-first order. Walking stops as soon as visit returns false.
+// The number of visited layers is limited by MaxUnwrapDepth.
+func walkChain(err error, visit func(err error) bool) bool {
+	return walkChainAt(err, 0, &unwrapWalk{}, visit)
+}
+
+func walkChainAt(err error, depth int, walk *unwrapWalk, visit func(err error) bool) bool {
+	for e := err; e != nil && walk.next(depth); e, depth = Unwrap(e), depth+1 {
+		if !visit(e) {
+			return false
+		}
+		if joined, ok := e.(interface{ Unwrap() []error }); ok && walk.enter(e) {
+			for _, branch := range joined.Unwrap() {
+				if !walkChainAt(branch, depth+1, walk, visit) {
+					return false
+				}
+			}
+		}
+	}
+
+	return true
+}
+
+// Fields returns all fields attached to errors in err's chain, ordered from the outermost
+// error to the innermost one. Fields of errors implementing LoggableError are included too.
+// Branches of joined errors are walked in depth-first order. Fields of errors joined by JoinInto
+// are merged by its strategy, so they are the same as in logs.
+//
+// Fields are not de-duplicated: if the same key is set at several layers, every field
+// is returned. Consumers that need a single value per key should take the first one,
+// which was set at the outermost layer.
+func Fields(err error) []Field {
+	collector := &fieldCollector{}
+	collectFieldsAt(err, collector, 0, &unwrapWalk{})
+
+	return collector.fields
+}
+
+// collectFieldsAt collects fields in the same way as logFieldsAt, but fields of errors
+// with the Fields method are collected as they are.
+func collectFieldsAt(err error, collector *fieldCollector, depth int, walk *unwrapWalk) {
+	for e := err; e != nil && walk.next(depth); e, depth = Unwrap(e), depth+1 {
+		if w, ok := e.(interface{ Fields() []Field }); ok {
+			collector.fields = append(collector.fields, w.Fields()...)
+		} else if loggable, ok := e.(LoggableError); ok {
+			loggable.LogFields(collector)
+		}
+
+		if _, ok := e.(*joinError); ok {
+			// fields of joined errors are merged by joinError itself
+			continue
+		}
+		if joined, ok := e.(interface{ Unwrap() []error }); ok && walk.enter(e) {
+			for _, branch := range joined.Unwrap() {
+				collectFieldsAt(branch, collector, depth+1, walk)
+			}
+		}
+	}
+}
+
+// FieldsMap returns all fields attached to errors in err's chain as a map of raw values
+// (stack traces are not included). Errors are walked in the same order as by Fields function:
+// from the outermost error to the innermost one, branches of joined errors in depth-first order.
+// If the same key is set several times, then the last written value wins, so values
+// of inner errors override values of outer ones, as it happens when marshaling into JSON.
+func FieldsMap(err error) map[string]interface{} {
+	data := make(map[string]interface{})
+	writer := keyValueLogger(func(key string, value interface{}) {
+		data[key] = value
+	})
+	for _, field := range Fields(err) {
+		field.Set(writer)
+	}
+
+	return data
+}
+
+// FieldsStringMap returns all fields attached to errors in err's chain as a map of strings,
+// for example, to be used as span tags of a tracing system. Values are rendered the same way
+// as in the "%+v" output: integers in decimal notation, floats in the shortest representation
+// (like "0.5" or "1e+21"), durations as "1.5s" and slices as comma-separated lists.
+// Stack traces are not included. As for FieldsMap, values of inner errors override values of outer ones.
+func FieldsStringMap(err error) map[string]string {
+	data := make(map[string]string)
+	logFields(err, textLogger(func(key string, value string) {
+		data[key] = value
+	}))
+
+	return data
+}
+
+// DuplicateKeys returns keys of fields that are set more than once in err's chain,
+// including fields of errors implementing LoggableError and of joined errors.
+// Keys are returned in order of their first appearance. It returns nil if all keys are unique.
+// It can be used in tests to assert uniqueness of keys, because duplicated values
+// override each other in logs and JSON.
+func DuplicateKeys(err error) []string {
+	var duplicates []string
+	counts := make(map[string]int)
+	counter := keyValueLogger(func(key string, value interface{}) {
+		counts[key]++
+		if counts[key] == 2 {
+			duplicates = append(duplicates, key)
+		}
+	})
+	for _, field := range Fields(err) {
+		field.Set(counter)
+	}
+
+	return duplicates
+}
+
+// GetField returns the value of the first field with the given key found in err's chain
+// and reports whether it was found. The chain is walked from the outermost error to the innermost
+// one, including branches of joined errors, so the value set at the outermost layer wins.
+// Values are returned as they were passed into the options, for example errors.Int("key", 1)
+// gives the int value 1. Values of redacted fields are returned raw.
+func GetField(err error, key string) (interface{}, bool) {
+	var value interface{}
+	found := false
+	reader := rawKeyValueLogger{keyValueLogger(func(k string, v interface{}) {
+		if !found && k == key {
+			value = v
+			found = true
+		}
+	})}
+
+	walkChain(err, func(e error) bool {
+		if loggable, ok := e.(LoggableError); ok {
+			loggable.LogFields(reader)
+		}
+		return !found
+	})
+
+	return value, found
+}
+
+// fieldCollector converts every value passed by LoggableError into a Field.
+// Stack trace is ignored.
+type fieldCollector struct {
+	fields []Field
+}
+
+func (c *fieldCollector) add(field Field) { c.fields = append(c.fields, field) }
+
+func (c *fieldCollector) SetBool(key string, value bool) { c.add(BoolField{Key: key, Value: value}) }
+func (c *fieldCollector) SetBools(key string, values []bool) {
+	c.add(BoolsField{Key: key, Values: values})
+}
+func (c *fieldCollector) SetInt(key string, value int) { c.add(IntField{Key: key, Value: value}) }
+func (c *fieldCollector) SetInts(key string, values []int) {
+	c.add(IntsField{Key: key, Values: values})
+}
+func (c *fieldCollector) SetInt32(key string, value int32) { c.add(Int32Field{Key: key, Value: value}) }
+func (c *fieldCollector) SetInt64(key string, value int64) { c.add(Int64Field{Key: key, Value: value}) }
+func (c *fieldCollector) SetUint(key string, value uint)   { c.add(UintField{Key: key, Value: value}) }
+func (c *fieldCollector) SetUint64(key string, value uint64) {
+	c.add(Uint64Field{Key: key, Value: value})
+}
+func (c *fieldCollector) SetFloat(key string, value float64) {
+	c.add(FloatField{Key: key, Value: value})
+}
+func (c *fieldCollector) SetString(key string, value string) {
+	c.add(StringField{Key: key, Value: value})
+}
+func (c *fieldCollector) SetTime(key string, value time.Time) {
+	c.add(TimeField{Key: key, Value: value})
+}
+func (c *fieldCollector) SetError(key string, value error) { c.add(ErrorField{Key: key, Value: value}) }
+func (c *fieldCollector) SetStackTrace(trace StackTrace)   {}
+
+func (c *fieldCollector) SetFloats(key string, values []float64) {
+	c.add(FloatsField{Key: key, Values: values})
+}
+
+func (c *fieldCollector) SetStrings(key string, values []string) {
+	c.add(StringsField{Key: key, Values: values})
+}
+
+func (c *fieldCollector) SetValue(key string, value interface{}) {
+	c.add(ValueField{Key: key, Value: value})
+}
+
+func (c *fieldCollector) SetDuration(key string, value time.Duration) {
+	c.add(DurationField{Key: key, Value: value})
+}
+
+func (c *fieldCollector) SetDurations(key string, values []time.Duration) {
+	c.add(DurationsField{Key: key, Values: values})
+}
+
+func (c *fieldCollector) SetJSON(key string, value json.RawMessage) {
+	c.add(JSONField{Key: key, Value: value})
+}
+
+func (c *fieldCollector) SetBytes(key string, value []byte, encoding BytesEncoding) {
+	c.add(BytesField{Key: key, Value: value, Encoding: encoding})
+}
+
+func (c *fieldCollector) setMarker(field Field) {
+	c.add(MarkerField{Field: field})
+}
+
+func (c *fieldCollector) setGroup(key string, fields []Field) {
+	c.add(GroupField{Key: key, Fields: fields})
+}
+
+func (c *fieldCollector) SetErrors(key string, values []error) {
+	c.add(ErrorsField{Key: key, Values: values})
+}
@@ -0,0 +1,722 @@
+package errors_test
+
+import (
+	"encoding/json"
+	stderrors "errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/muonsoft/errors"
+	"github.com/muonsoft/errors/errorstest"
+)
+
+func TestDiagnoseChain(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want []string
+	}{
+		{
+			name: "nil",
+			err:  nil,
+			want: nil,
+		},
+		{
+			name: "sentinel error",
+			err:  errors.New("ooh"),
+			want: nil,
+		},
+		{
+			name: "errors.Errorf chain",
+			err:  errors.Errorf("a: %w", errors.Errorf("b: %w", errors.Wrap(errors.New("c")))),
+			want: nil,
+		},
+		{
+			name: "fmt.Errorf wrapped by errors.Errorf",
+			err:  errors.Errorf("a: %w", fmt.Errorf("b: %w", errors.New("c"))),
+			want: nil,
+		},
+		{
+			name: "mixed fmt.Errorf and errors.Errorf chain",
+			err:  errors.Errorf("a: %w", fmt.Errorf("b: %w", errors.Errorf("c: %w", errors.New("d")))),
+			want: []string{
+				"layer 2 (*fmt.wrapError) is not a wrapper; fields may be unreachable",
+			},
+		},
+		{
+			name: "fmt.Errorf on top of errors.Errorf",
+			err:  fmt.Errorf("a: %w", errors.Errorf("b", errors.String("key", "value"))),
+			want: []string{
+				"layer 1 (*fmt.wrapError) is not a wrapper; fields may be unreachable",
+			},
+		},
+		{
+			name: "second stack trace",
+			err:  errors.Errorf("a: %w", &foreignStackError{err: errors.Errorf("b")}),
+			want: []string{
+				"layer 2 (*errors_test.foreignStackError) is not a wrapper; fields may be unreachable",
+				"layer 3 (*errors.stacked) has a second stack trace",
+			},
+		},
+		{
+			name: "Temporary wrapped by errors.Errorf",
+			err:  errors.Errorf("x: %w", errors.Temporary(errors.Errorf("y"))),
+			want: nil,
+		},
+		{
+			name: "StripStack output",
+			err:  errors.StripStack(errors.Errorf("a: %w", fmt.Errorf("b: %w", errors.Errorf("c", errors.String("key", "value"))))),
+			want: nil,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := errors.DiagnoseChain(test.err)
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("want diagnostics %q, got %q", test.want, got)
+			}
+		})
+	}
+}
+
+type foreignStackError struct {
+	err error
+}
+
+func (e *foreignStackError) Error() string                 { return e.err.Error() }
+func (e *foreignStackError) Unwrap() error                 { return e.err }
+func (e *foreignStackError) StackTrace() errors.StackTrace { return nil }
+
+func TestBreadcrumbs(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want []string
+	}{
+		{
+			name: "nil",
+			err:  nil,
+			want: nil,
+		},
+		{
+			name: "sentinel error",
+			err:  errors.New("no rows"),
+			want: []string{"no rows"},
+		},
+		{
+			name: "three-layer Errorf chain",
+			err:  errors.Errorf("find product: %w", errors.Errorf("query: %w", errors.New("no rows"))),
+			want: []string{"find product", "query", "no rows"},
+		},
+		{
+			name: "Wrap does not add a breadcrumb",
+			err:  errors.Wrap(errors.Errorf("query: %w", errors.Wrap(errors.New("no rows")))),
+			want: []string{"query", "no rows"},
+		},
+		{
+			name: "message after wrapped error",
+			err:  errors.Errorf("%w (retrying)", fmt.Errorf("connect: %w", errors.New("refused"))),
+			want: []string{"(retrying)", "connect", "refused"},
+		},
+		{
+			name: "separators in message text",
+			err:  errors.Errorf("-1 is bad: %w", errors.New("x")),
+			want: []string{"-1 is bad", "x"},
+		},
+		{
+			name: "inner message inside prefix",
+			err:  errors.Errorf("x y: %w", errors.New("x")),
+			want: []string{"x y", "x"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := errors.Breadcrumbs(test.err)
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("want breadcrumbs %q, got %q", test.want, got)
+			}
+		})
+	}
+}
+
+func TestTypePath(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{
+			name: "nil",
+			err:  nil,
+			want: "",
+		},
+		{
+			name: "sentinel error",
+			err:  errors.New("ooh"),
+			want: "*errorString",
+		},
+		{
+			name: "wrapped custom error",
+			err:  errors.Wrap(&ForbiddenError{Action: "DoSomething", UserID: 1}),
+			want: "*stacked>*ForbiddenError",
+		},
+		{
+			name: "wrapped custom error with message",
+			err:  errors.Errorf("do something: %w", &ForbiddenError{Action: "DoSomething", UserID: 1}),
+			want: "*stacked>*wrapError>*ForbiddenError",
+		},
+		{
+			name: "wrapped custom error with fields",
+			err: errors.Wrap(
+				errors.Wrap(&ForbiddenError{Action: "DoSomething", UserID: 1}),
+				errors.String("key", "value"),
+			),
+			want: "*wrapped>*stacked>*ForbiddenError",
+		},
+		{
+			name: "value error type",
+			err:  wrapped{"wrapped", errorT{"T"}},
+			want: "wrapped>errorT",
+		},
+		{
+			name: "joined errors",
+			err:  errors.Join(errors.New("ooh"), errors.Wrap(&ForbiddenError{Action: "DoSomething", UserID: 1})),
+			want: "*stacked>*joinError(*errorString,*stacked>*ForbiddenError)",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := errors.TypePath(test.err)
+			if got != test.want {
+				t.Errorf("want type path %q, got %q", test.want, got)
+			}
+		})
+	}
+}
+
+func TestFieldsOfChain(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want []errors.Field
+	}{
+		{
+			name: "nil",
+			err:  nil,
+			want: nil,
+		},
+		{
+			name: "error without fields",
+			err:  errors.Wrap(errors.New("ooh")),
+			want: nil,
+		},
+		{
+			name: "wrapped errors",
+			err: errors.Wrap(
+				errors.Wrap(
+					errors.Errorf("ooh", errors.String("deepestKey", "deepestValue")),
+					errors.String("deepKey", "deepValue"),
+				),
+				errors.String("key", "value"),
+			),
+			want: []errors.Field{
+				errors.StringField{Key: "key", Value: "value"},
+				errors.StringField{Key: "deepKey", Value: "deepValue"},
+				errors.StringField{Key: "deepestKey", Value: "deepestValue"},
+			},
+		},
+		{
+			name: "duplicated keys",
+			err:  errors.Wrap(errors.Errorf("ooh", errors.Int("key", 1)), errors.Int("key", 2)),
+			want: []errors.Field{
+				errors.IntField{Key: "key", Value: 2},
+				errors.IntField{Key: "key", Value: 1},
+			},
+		},
+		{
+			name: "loggable error",
+			err:  errors.Wrap(&ForbiddenError{Action: "DoSomething", UserID: 1}, errors.String("key", "value")),
+			want: []errors.Field{
+				errors.StringField{Key: "key", Value: "value"},
+				errors.StringField{Key: "action", Value: "DoSomething"},
+				errors.IntField{Key: "userID", Value: 1},
+			},
+		},
+		{
+			name: "joined errors",
+			err: errors.Wrap(
+				errors.Join(
+					errors.Wrap(
+						errors.Errorf("error 1", errors.String("key1", "value1")),
+						errors.String("key2", "value2"),
+					),
+					errors.Errorf("error 2", errors.String("key3", "value3")),
+					stderrors.Join(
+						errors.Errorf("error 3", errors.String("key4", "value4")),
+						&ForbiddenError{Action: "DoSomething", UserID: 1},
+					),
+				),
+				errors.String("key", "value"),
+			),
+			want: []errors.Field{
+				errors.StringField{Key: "key", Value: "value"},
+				errors.StringField{Key: "key2", Value: "value2"},
+				errors.StringField{Key: "key1", Value: "value1"},
+				errors.StringField{Key: "key3", Value: "value3"},
+				errors.StringField{Key: "key4", Value: "value4"},
+				errors.StringField{Key: "action", Value: "DoSomething"},
+				errors.IntField{Key: "userID", Value: 1},
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := errors.Fields(test.err)
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("want fields %#v, got %#v", test.want, got)
+			}
+		})
+	}
+}
+
+func TestGetField(t *testing.T) {
+	err := errors.Wrap(
+		errors.Errorf(
+			"find product: %w",
+			errors.Join(
+				errors.New("ooh"),
+				errors.Wrap(
+					errors.Errorf("query", errors.Int("productID", 123)),
+					errors.String("table", "products"),
+				),
+			),
+		),
+		errors.String("key", "value"),
+	)
+
+	tests := []struct {
+		key       string
+		wantValue interface{}
+		wantFound bool
+	}{
+		{key: "key", wantValue: "value", wantFound: true},
+		{key: "table", wantValue: "products", wantFound: true},
+		{key: "productID", wantValue: 123, wantFound: true},
+		{key: "notExisting", wantValue: nil, wantFound: false},
+	}
+	for _, test := range tests {
+		t.Run(test.key, func(t *testing.T) {
+			value, found := errors.GetField(err, test.key)
+			if found != test.wantFound {
+				t.Errorf("want found to be %t, got %t", test.wantFound, found)
+			}
+			if value != test.wantValue {
+				t.Errorf("want value %#v, got %#v", test.wantValue, value)
+			}
+		})
+	}
+}
+
+func TestGetField_nil(t *testing.T) {
+	value, found := errors.GetField(nil, "key")
+	if found || value != nil {
+		t.Errorf("want no value for nil error, got %#v", value)
+	}
+}
+
+func TestChain(t *testing.T) {
+	sentinel := errors.New("ooh")
+	tests := []struct {
+		name string
+		err  error
+		want []string
+	}{
+		{
+			name: "nil",
+			err:  nil,
+			want: nil,
+		},
+		{
+			name: "sentinel error",
+			err:  sentinel,
+			want: []string{`*errors.errorString "ooh"`},
+		},
+		{
+			name: "wrapped errors",
+			err: errors.Wrap(
+				errors.Wrap(
+					errors.Errorf("ooh", errors.String("deepestKey", "deepestValue")),
+					errors.String("deepKey", "deepValue"),
+				),
+				errors.String("key", "value"),
+			),
+			want: []string{
+				`*errors.wrapped "ooh"`,
+				`*errors.wrapped "ooh"`,
+				`*errors.stacked "ooh"`,
+				`*errors.errorString "ooh"`,
+			},
+		},
+		{
+			name: "joined errors",
+			err: errors.Wrap(
+				errors.Join(
+					errors.Wrap(
+						errors.Errorf("error 1", errors.String("key1", "value1")),
+						errors.String("key2", "value2"),
+					),
+					errors.Errorf("error 2", errors.String("key3", "value3")),
+					stderrors.Join(
+						errors.Errorf("error 3", errors.String("key4", "value4")),
+						errors.Errorf("error 4", errors.String("key5", "value5")),
+					),
+				),
+			),
+			want: []string{
+				`*errors.stacked "error 1\nerror 2\nerror 3\nerror 4"`,
+				`*errors.joinError "error 1\nerror 2\nerror 3\nerror 4"`,
+				`*errors.wrapped "error 1"`,
+				`*errors.stacked "error 1"`,
+				`*errors.errorString "error 1"`,
+				`*errors.stacked "error 2"`,
+				`*errors.errorString "error 2"`,
+				`*errors.joinError "error 3\nerror 4"`,
+				`*errors.stacked "error 3"`,
+				`*errors.errorString "error 3"`,
+				`*errors.stacked "error 4"`,
+				`*errors.errorString "error 4"`,
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var got []string
+			for _, err := range errors.Chain(test.err) {
+				got = append(got, fmt.Sprintf("%T %q", err, err.Error()))
+			}
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("want chain %q, got %q", test.want, got)
+			}
+		})
+	}
+}
+
+func TestRoot(t *testing.T) {
+	sentinel := errors.New("ooh")
+	tests := []struct {
+		name string
+		err  error
+		want error
+	}{
+		{
+			name: "nil",
+			err:  nil,
+			want: nil,
+		},
+		{
+			name: "sentinel error",
+			err:  sentinel,
+			want: sentinel,
+		},
+		{
+			name: "three-level chain",
+			err: errors.Wrap(
+				errors.Errorf("find product: %w", errors.Wrap(sentinel)),
+				errors.String("key", "value"),
+			),
+			want: sentinel,
+		},
+		{
+			name: "joined errors",
+			err:  errors.Errorf("join: %w", errors.Join(errors.Wrap(sentinel), errors.New("other"))),
+			want: sentinel,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := errors.Root(test.err); got != test.want {
+				t.Errorf("want root %v, got %v", test.want, got)
+			}
+		})
+	}
+}
+
+func TestFieldsMap(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want map[string]interface{}
+	}{
+		{
+			name: "nil",
+			err:  nil,
+			want: map[string]interface{}{},
+		},
+		{
+			name: "wrapped errors",
+			err: errors.Wrap(
+				errors.Wrap(
+					errors.Errorf("ooh", errors.String("deepestKey", "deepestValue"), errors.Int("duplicated", 1)),
+					errors.String("deepKey", "deepValue"),
+				),
+				errors.String("key", "value"),
+				errors.Int("duplicated", 2),
+			),
+			want: map[string]interface{}{
+				"key":        "value",
+				"deepKey":    "deepValue",
+				"deepestKey": "deepestValue",
+				"duplicated": 1,
+			},
+		},
+		{
+			name: "joined errors",
+			err: errors.Wrap(
+				errors.Join(
+					errors.Wrap(
+						errors.Errorf("error 1", errors.String("key1", "value1")),
+						errors.String("key2", "value2"),
+					),
+					errors.Errorf("error 2", errors.String("key3", "value3")),
+					stderrors.Join(
+						errors.Errorf("error 3", errors.String("key4", "value4")),
+						&ForbiddenError{Action: "DoSomething", UserID: 1},
+					),
+				),
+			),
+			want: map[string]interface{}{
+				"key1":   "value1",
+				"key2":   "value2",
+				"key3":   "value3",
+				"key4":   "value4",
+				"action": "DoSomething",
+				"userID": 1,
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := errors.FieldsMap(test.err)
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("want fields %#v, got %#v", test.want, got)
+			}
+		})
+	}
+}
+
+// causer is an error in the style of github.com/pkg/errors.
+type causer struct {
+	msg   string
+	cause error
+}
+
+func (e *causer) Error() string { return e.msg + ": " + e.cause.Error() }
+func (e *causer) Cause() error  { return e.cause }
+
+func TestCause(t *testing.T) {
+	sentinel := errors.New("ooh")
+	tests := []struct {
+		name string
+		err  error
+		want error
+	}{
+		{
+			name: "nil",
+			err:  nil,
+			want: nil,
+		},
+		{
+			name: "sentinel error",
+			err:  sentinel,
+			want: sentinel,
+		},
+		{
+			name: "causer wrapping errors.Errorf",
+			err:  &causer{msg: "legacy", cause: errors.Errorf("find product: %w", sentinel)},
+			want: sentinel,
+		},
+		{
+			name: "errors.Wrap wrapping causer",
+			err:  errors.Wrap(&causer{msg: "legacy", cause: errors.Wrap(sentinel)}),
+			want: sentinel,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := errors.Cause(test.err); got != test.want {
+				t.Errorf("want cause %v, got %v", test.want, got)
+			}
+		})
+	}
+}
+
+func TestDuplicateKeys(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want []string
+	}{
+		{
+			name: "unique keys",
+			err: errors.Wrap(
+				errors.Errorf("ooh", errors.Int("productID", 1)),
+				errors.Int("userID", 2),
+			),
+			want: nil,
+		},
+		{
+			name: "duplicated keys",
+			err: errors.Wrap(
+				errors.Join(
+					errors.Errorf("error 1", errors.Int("userID", 1), errors.String("action", "read")),
+					errors.Wrap(&ForbiddenError{Action: "DoSomething", UserID: 1}, errors.Int("userID", 3)),
+				),
+				errors.Int("userID", 2),
+			),
+			want: []string{"userID", "action"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := errors.DuplicateKeys(test.err)
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("want duplicated keys %q, got %q", test.want, got)
+			}
+		})
+	}
+}
+
+func TestFind(t *testing.T) {
+	target := errors.Errorf("not found", errors.Int("code", 404))
+	err := errors.Join(
+		errors.Errorf("bad request", errors.Int("code", 400)),
+		errors.Errorf("find product: %w", target),
+	)
+	hasCode := func(code int) func(err error) bool {
+		return func(err error) bool {
+			value, _ := errors.GetField(err, "code")
+			return value == code
+		}
+	}
+
+	found, ok := errors.Find(err, hasCode(404))
+
+	if !ok {
+		t.Fatal("want error to be found")
+	}
+	if value, _ := errors.GetField(found, "code"); value != 404 {
+		t.Errorf("want found error to have code 404, got %v", value)
+	}
+	if !errors.Is(found, target) {
+		t.Errorf("want found error to be %v, got %v", target, found)
+	}
+	if found, ok := errors.Find(err, hasCode(500)); ok || found != nil {
+		t.Errorf("want no error to be found, got %v", found)
+	}
+}
+
+func TestFieldsStringMap(t *testing.T) {
+	timestamp := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	err := errors.Wrap(
+		errors.Errorf("ooh", errors.Int("id", 123), errors.Float("ratio", 0.5)),
+		errors.Duration("elapsed", 1500*time.Millisecond),
+		errors.Time("time", timestamp),
+		errors.Strings("tags", []string{"a", "b"}),
+	)
+
+	got := errors.FieldsStringMap(err)
+
+	want := map[string]string{
+		"id":      "123",
+		"ratio":   "0.5",
+		"elapsed": "1.5s",
+		"time":    "2024-05-01T10:00:00Z",
+		"tags":    "a, b",
+	}
+	if len(got) != len(want) {
+		t.Errorf("want %v, got %v", want, got)
+	}
+	for key, value := range want {
+		if got[key] != value {
+			t.Errorf("want field %q to be %q, got %q", key, value, got[key])
+		}
+	}
+}
+
+// cyclicError is a malformed error which Unwrap method returns the error itself.
+type cyclicError struct{}
+
+func (e *cyclicError) Error() string { return "cyclic" }
+func (e *cyclicError) Unwrap() error { return e }
+
+// cyclicJoinError is a malformed error which Unwrap method returns the error itself as a branch.
+type cyclicJoinError struct{}
+
+func (e *cyclicJoinError) Error() string   { return "cyclic join" }
+func (e *cyclicJoinError) Unwrap() []error { return []error{e} }
+
+// cyclicPairError is a malformed error which Unwrap method returns the error itself as two branches.
+type cyclicPairError struct{}
+
+func (e *cyclicPairError) Error() string   { return "cyclic pair" }
+func (e *cyclicPairError) Unwrap() []error { return []error{e, e} }
+
+// loopyError is a malformed error which chain leads back to the error itself through other errors.
+type loopyError struct {
+	inner error
+}
+
+func (e *loopyError) Error() string { return "loopy" }
+func (e *loopyError) Unwrap() error { return e.inner }
+
+func TestMaxUnwrapDepth_cycles(t *testing.T) {
+	loopy := &loopyError{}
+	loopy.inner = errors.Wrap(loopy)
+	for _, cyclic := range []error{&cyclicError{}, &cyclicJoinError{}, &cyclicPairError{}, loopy} {
+		tests := []struct {
+			name string
+			err  error
+		}{
+			{name: "Errorf", err: errors.Errorf("wrapped: %w", cyclic, errors.String("key", "value"))},
+			{name: "Wrap", err: errors.Wrap(errors.Wrap(cyclic), errors.String("key", "value"))},
+			{name: "Join", err: errors.Join(cyclic, errors.Errorf("second", errors.String("key", "value")))},
+			{
+				name: "JoinInto",
+				err:  errors.JoinInto(errors.NumberedSuffix, cyclic, errors.Errorf("second", errors.String("key", "value"))),
+			},
+		}
+		for _, test := range tests {
+			t.Run(cyclic.Error()+"/"+test.name, func(t *testing.T) {
+				err := test.err
+
+				if _, ok := errors.As[*fs.PathError](err); ok {
+					t.Error("want no match")
+				}
+				if got := len(errors.Fields(err)); got != 1 {
+					t.Errorf("want 1 field, got %d", got)
+				}
+				logger := errorstest.NewLogger()
+				errors.Log(err, logger)
+				logger.AssertField(t, "key", "value")
+				if _, e := json.Marshal(err); e != nil {
+					t.Errorf("want error to be marshaled into json: %v", e)
+				}
+				_ = fmt.Sprintf("%+v", err)
+				errors.WriteLogfmt(io.Discard, err)
+				_ = errors.FieldsMap(err)
+				_ = errors.FieldsStringMap(err)
+				_ = errors.Chain(err)
+				_ = errors.Root(err)
+				_ = errors.Cause(err)
+				_ = errors.TypePath(err)
+				_ = errors.Breadcrumbs(err)
+				_ = errors.DiagnoseChain(err)
+				_ = errors.StripStack(err)
+			})
+		}
+	}
+}
@@ -0,0 +1,75 @@
+package errors
+
+import "context"
+
+// Temporary marks err as temporary: IsTemporary will report true for it and for any error
+// wrapping it. The message of the error is not changed. If err is nil, Temporary returns nil.
+func Temporary(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	return &temporaryError{err: err}
+}
+
+// IsTemporary reports whether any error in err's chain (including branches of joined errors)
+// implements interface{ Temporary() bool } and its method Temporary returns true.
+func IsTemporary(err error) bool {
+	temporary := false
+	walkChain(err, func(e error) bool {
+		if t, ok := e.(interface{ Temporary() bool }); ok && t.Temporary() {
+			temporary = true
+		}
+		return !temporary
+	})
+
+	return temporary
+}
+
+type temporaryError struct {
+	err error
+}
+
+func (e *temporaryError) Error() string   { return e.err.Error() }
+func (e *temporaryError) Unwrap() error   { return e.err }
+func (e *temporaryError) Temporary() bool { return true }
+
+const timeoutKey = "timeout"
+
+// Timeout marks an error as caused by a timeout, so IsTimeout reports true for it.
+// The mark is set as a bool field "timeout", so it is visible in logs and survives wrapping.
+func Timeout() Option {
+	return Marker(BoolField{Key: timeoutKey, Value: true})
+}
+
+// IsTimeout reports whether err matches context.DeadlineExceeded by Is, or any error in err's chain
+// (including branches of joined errors) implements interface{ Timeout() bool } and its method
+// Timeout returns true (like network errors), or is marked by Timeout option.
+func IsTimeout(err error) bool {
+	if Is(err, context.DeadlineExceeded) {
+		return true
+	}
+
+	timeout := false
+	walkChain(err, func(e error) bool {
+		if t, ok := e.(interface{ Timeout() bool }); ok && t.Timeout() {
+			timeout = true
+		}
+		return !timeout
+	})
+	if timeout {
+		return true
+	}
+
+	value, _ := GetField(err, timeoutKey)
+	timeout, _ = value.(bool)
+
+	return timeout
+}
+
+// IsCanceled reports whether any error in err's chain (including branches of joined errors)
+// is context.Canceled. It is equivalent to Is(err, context.Canceled), but reads better
+// in request handlers that skip logging of errors caused by cancellation of a request.
+func IsCanceled(err error) bool {
+	return Is(err, context.Canceled)
+}
@@ -0,0 +1,161 @@
+package errors_test
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"testing"
+
+	"github.com/muonsoft/errors"
+)
+
+type temporaryError struct {
+	temporary bool
+}
+
+func (e *temporaryError) Error() string   { return "temporary" }
+func (e *temporaryError) Temporary() bool { return e.temporary }
+
+// matchingError matches the target by its Is method.
+type matchingError struct {
+	target error
+}
+
+func (e *matchingError) Error() string        { return "matching" }
+func (e *matchingError) Is(target error) bool { return target == e.target }
+
+func TestIsTemporary(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{name: "nil", err: nil, want: false},
+		{name: "sentinel error", err: errTest, want: false},
+		{name: "marked error", err: errors.Temporary(errTest), want: true},
+		{
+			name: "wrapped marked error",
+			err:  errors.Errorf("find product: %w", errors.Wrap(errors.Temporary(errTest))),
+			want: true,
+		},
+		{
+			name: "wrapped temporary error",
+			err:  errors.Wrap(&temporaryError{temporary: true}, errors.String("key", "value")),
+			want: true,
+		},
+		{
+			name: "wrapped non-temporary error",
+			err:  errors.Wrap(&temporaryError{temporary: false}),
+			want: false,
+		},
+		{
+			name: "temporary error wrapping non-temporary one",
+			err:  errors.Temporary(errors.Wrap(&temporaryError{temporary: false})),
+			want: true,
+		},
+		{
+			name: "joined temporary error",
+			err:  errors.Join(errTest, errors.Temporary(errors.New("ooh"))),
+			want: true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := errors.IsTemporary(test.err); got != test.want {
+				t.Errorf("want IsTemporary to be %t, got %t", test.want, got)
+			}
+		})
+	}
+}
+
+func TestTemporary(t *testing.T) {
+	err := errors.Temporary(errTest)
+
+	if err.Error() != errTest.Error() {
+		t.Errorf("want message %q, got %q", errTest.Error(), err.Error())
+	}
+	if !errors.Is(err, errTest) {
+		t.Errorf("want %#v to wrap %#v", err, errTest)
+	}
+	if errors.Temporary(nil) != nil {
+		t.Error("want nil for nil error")
+	}
+}
+
+func TestIsTimeout(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{name: "nil", err: nil},
+		{name: "plain", err: errors.Errorf("ooh")},
+		{name: "canceled", err: errors.Wrap(context.Canceled)},
+		{
+			name: "wrapped context deadline",
+			err:  errors.Wrap(fmt.Errorf("query: %w", errors.Errorf("fetch: %w", context.DeadlineExceeded))),
+			want: true,
+		},
+		{
+			name: "wrapped net timeout",
+			err:  errors.Errorf("resolve: %w", &net.DNSError{Err: "i/o timeout", Name: "example.com", IsTimeout: true}),
+			want: true,
+		},
+		{
+			name: "net error without timeout",
+			err:  errors.Errorf("resolve: %w", &net.DNSError{Err: "no such host", Name: "example.com"}),
+		},
+		{
+			name: "joined",
+			err:  errors.Join(errors.New("first"), errors.Wrap(context.DeadlineExceeded)),
+			want: true,
+		},
+		{name: "marked", err: errors.Wrap(errors.Errorf("ooh", errors.Timeout())), want: true},
+		{
+			name: "matched by Is method",
+			err:  errors.Wrap(&matchingError{target: context.DeadlineExceeded}),
+			want: true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := errors.IsTimeout(test.err); got != test.want {
+				t.Errorf("IsTimeout() = %t, want %t", got, test.want)
+			}
+		})
+	}
+}
+
+func TestIsCanceled(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{name: "nil", err: nil},
+		{name: "plain", err: errors.Errorf("ooh")},
+		{name: "deadline", err: errors.Wrap(context.DeadlineExceeded)},
+		{
+			name: "wrapped",
+			err:  errors.Wrap(fmt.Errorf("query: %w", errors.Errorf("fetch: %w", context.Canceled))),
+			want: true,
+		},
+		{
+			name: "joined",
+			err:  errors.Wrap(fmt.Errorf("%w; %w", errors.New("first"), errors.Wrap(context.Canceled))),
+			want: true,
+		},
+		{
+			name: "matched by Is method",
+			err:  errors.Wrap(&matchingError{target: context.Canceled}),
+			want: true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := errors.IsCanceled(test.err); got != test.want {
+				t.Errorf("IsCanceled() = %t, want %t", got, test.want)
+			}
+		})
+	}
+}
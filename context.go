@@ -0,0 +1,23 @@
+package errors
+
+import "context"
+
+// ContextKey describes a value to be read from a context by WithContext option:
+// Key is the key of the value in the context and Field is the key of the error field.
+type ContextKey struct {
+	Key   interface{}
+	Field string
+}
+
+// WithContext adds values of the given keys from the context as error fields,
+// for example to attach request-scoped identifiers like a trace ID or a user ID.
+// Keys missing in the context (with nil values) are skipped silently.
+func WithContext(ctx context.Context, keys ...ContextKey) Option {
+	return func(options *Options) {
+		for _, key := range keys {
+			if value := ctx.Value(key.Key); value != nil {
+				options.AddField(ValueField{Key: key.Field, Value: value})
+			}
+		}
+	}
+}
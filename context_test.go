@@ -0,0 +1,31 @@
+package errors_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/muonsoft/errors"
+	"github.com/muonsoft/errors/errorstest"
+)
+
+type contextKey string
+
+func TestWithContext(t *testing.T) {
+	ctx := context.WithValue(context.Background(), contextKey("traceID"), "abc")
+	ctx = context.WithValue(ctx, contextKey("userID"), 123)
+
+	err := errors.Errorf("ooh", errors.WithContext(
+		ctx,
+		errors.ContextKey{Key: contextKey("traceID"), Field: "traceID"},
+		errors.ContextKey{Key: contextKey("userID"), Field: "userID"},
+		errors.ContextKey{Key: contextKey("missing"), Field: "missing"},
+	))
+
+	logger := errorstest.NewLogger()
+	errors.Log(err, logger)
+	logger.AssertField(t, "traceID", "abc")
+	logger.AssertField(t, "userID", 123)
+	if _, ok := logger.Fields["missing"]; ok {
+		t.Error(`want missing key to be skipped`)
+	}
+}
@@ -21,6 +21,7 @@ import (
 	"fmt"
 	"io"
 	"strconv"
+	"strings"
 	"time"
 )
 
@@ -33,6 +34,36 @@ func New(message string) error {
 	return errors.New(message)
 }
 
+const definedAtKey = "definedAt"
+
+// NewSentinel returns a sentinel error like New, but also records the place where it is defined.
+// The place is set as a string field "definedAt" in the "file:line" format, so it is logged
+// and marshaled with the error. The sentinel itself does not contain a stack trace,
+// so a stack trace is recorded when it is wrapped as usual.
+func NewSentinel(message string) error {
+	e := &sentinel{message: message}
+	if pcs := callers(0, 1); len(pcs) > 0 {
+		frame := Frame(pcs[0])
+		e.definedAt = frame.File() + ":" + strconv.Itoa(frame.Line())
+	}
+
+	return e
+}
+
+// sentinel is an error created by NewSentinel.
+type sentinel struct {
+	message   string
+	definedAt string
+}
+
+func (e *sentinel) Error() string {
+	return e.message
+}
+
+func (e *sentinel) LogFields(logger FieldLogger) {
+	MarkerField{Field: StringField{Key: definedAtKey, Value: e.definedAt}}.Set(logger)
+}
+
 // Is reports whether any error in err's chain matches target.
 //
 // The chain consists of err itself followed by the sequence of errors obtained by
@@ -46,6 +77,29 @@ func Is(err, target error) bool {
 	return errors.Is(err, target)
 }
 
+// IsAny reports whether any error in err's chain matches any of the targets.
+// It stops at the first matching target. It returns false if no targets are given.
+func IsAny(err error, targets ...error) bool {
+	for _, target := range targets {
+		if Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsAll reports whether every target is matched by some error in err's chain.
+// It is useful to check joined errors and stops at the first target that does not match.
+// It returns true if no targets are given.
+func IsAll(err error, targets ...error) bool {
+	for _, target := range targets {
+		if !Is(err, target) {
+			return false
+		}
+	}
+	return true
+}
+
 // As finds the first error in err's chain that matches type T, and if one is found, returns
 // its value and true. Otherwise, it returns zero value and false.
 //
@@ -59,7 +113,11 @@ func Is(err, target error) bool {
 // An error type might provide an As method so it can be treated as if it were a
 // different error type.
 func As[T any](err error) (T, bool) {
-	for err != nil {
+	return as[T](err, 0, &unwrapWalk{})
+}
+
+func as[T any](err error, depth int, walk *unwrapWalk) (T, bool) {
+	for ; err != nil && walk.next(depth); depth++ {
 		if t, ok := err.(T); ok {
 			return t, true
 		}
@@ -77,8 +135,12 @@ func As[T any](err error) (T, bool) {
 				return z, false
 			}
 		case interface{ Unwrap() []error }:
+			if !walk.enter(err) {
+				var z T
+				return z, false
+			}
 			for _, err := range x.Unwrap() {
-				if t, ok := As[T](err); ok {
+				if t, ok := as[T](err, depth+1, walk); ok {
 					return t, ok
 				}
 			}
@@ -103,6 +165,30 @@ func IsOfType[T any](err error) bool {
 	return is
 }
 
+// AsAll finds every error in err's chain that matches type T and returns their values.
+// Errors are matched the same way as by As function, but searching does not stop
+// at the first match.
+//
+// Errors are returned in the order of Chain function: from the outermost error to the innermost
+// one, branches of joined errors are walked in depth-first order. It returns nil if nothing matches.
+func AsAll[T any](err error) []T {
+	var matches []T
+
+	walkChain(err, func(e error) bool {
+		if t, ok := e.(T); ok {
+			matches = append(matches, t)
+		} else if x, ok := e.(interface{ As(any) bool }); ok {
+			var t T
+			if x.As(&t) {
+				matches = append(matches, t)
+			}
+		}
+		return true
+	})
+
+	return matches
+}
+
 // Unwrap returns the result of calling the Unwrap method on err, if err's
 // type contains an Unwrap method returning error.
 // Otherwise, Unwrap returns nil.
@@ -115,23 +201,44 @@ func Unwrap(err error) error {
 // Errorf formats according to a format specifier and returns the string
 // as a value that satisfies error. You can wrap an error using %w modifier as it
 // does fmt.Errorf function.
-// Errorf also records the stack trace at the point it was called. If the wrapped error
+// Errorf also records the stack trace at the point it was called. If any of the wrapped errors
 // contains a stack trace then a new one will not be added to a chain.
 // Also, you can pass an options to set a structured fields or to skip a caller
 // in a stack trace. Options must be specified after formatting arguments.
+// An option formatted by the %v verb is treated as a formatting argument, not as an option.
 func Errorf(message string, argsAndOptions ...interface{}) error {
-	args, options := splitArgsAndOptions(argsAndOptions)
+	args, options := splitArgsAndOptions(message, argsAndOptions)
 	opts := newOptions(options...)
 	err := fmt.Errorf(message, args...)
 
 	argErrors := getArgErrors(message, args)
-	if len(argErrors) == 1 && isWrapper(argErrors[0]) {
+	if opts.noStack || hasAnyStack(argErrors) {
 		return &wrapped{wrapped: err, fields: opts.fields}
 	}
 
 	return &stacked{
-		wrapped: &wrapped{wrapped: err, fields: opts.fields},
-		stack:   newStack(opts.skipCallers),
+		wrapped: wrapped{wrapped: err, fields: opts.fields},
+		stack:   stack{pcs: opts.filterCallers(callers(opts.skipCallers, opts.stackDepth)), topFrames: opts.topFrames},
+	}
+}
+
+// ErrorfSkip works like Errorf, but skips the given number of callers in the recorded stack trace,
+// so it is convenient for helper functions and factories of errors: ErrorfSkip(0, ...) records
+// the stack trace at the point ErrorfSkip is called and ErrorfSkip(1, ...) at the point
+// the function calling ErrorfSkip is called. Negative values are treated as 0.
+// Unlike Errorf, all arguments are passed to the formatter, so options are not accepted.
+func ErrorfSkip(skip int, format string, args ...interface{}) error {
+	err := fmt.Errorf(format, args...)
+	if hasAnyStack(getArgErrors(format, args)) {
+		return &wrapped{wrapped: err}
+	}
+	if skip < 0 {
+		skip = 0
+	}
+
+	return &stacked{
+		wrapped: wrapped{wrapped: err},
+		stack:   stack{pcs: callers(skip, 0)},
 	}
 }
 
@@ -144,7 +251,7 @@ func Wrap(err error, options ...Option) error {
 	if err == nil {
 		return nil
 	}
-	if isWrapper(err) {
+	if hasStack(err) {
 		if len(options) == 0 {
 			return err
 		}
@@ -153,13 +260,115 @@ func Wrap(err error, options ...Option) error {
 	}
 
 	opts := newOptions(options...)
+	if opts.noStack {
+		return &wrapped{wrapped: err, fields: opts.fields}
+	}
+
+	return &stacked{
+		wrapped: wrapped{wrapped: err, fields: opts.fields},
+		stack:   stack{pcs: opts.filterCallers(callers(opts.skipCallers, opts.stackDepth)), topFrames: opts.topFrames},
+	}
+}
+
+// Wrapf returns an error annotating err with a message formatted according to a format specifier.
+// The message of the returned error is "<formatted message>: <err message>" and err is kept in the chain.
+// As for Wrap, a stack trace is recorded only if the wrapped error does not contain one.
+// If err is nil, Wrapf returns nil.
+// Options to set structured fields or to skip a caller in a stack trace
+// must be specified after formatting arguments. As for Errorf, an option formatted
+// by the %v verb is treated as a formatting argument.
+func Wrapf(err error, format string, argsAndOptions ...interface{}) error {
+	if err == nil {
+		return nil
+	}
+
+	args, options := splitArgsAndOptions(format, argsAndOptions)
+	opts := newOptions(options...)
+	e := fmt.Errorf("%s: %w", fmt.Sprintf(format, args...), err)
+
+	if opts.noStack || hasStack(err) {
+		return &wrapped{wrapped: e, fields: opts.fields}
+	}
+
+	return &stacked{
+		wrapped: wrapped{wrapped: e, fields: opts.fields},
+		stack:   stack{pcs: opts.filterCallers(callers(opts.skipCallers, opts.stackDepth)), topFrames: opts.topFrames},
+	}
+}
+
+// WithStack annotates err with a stack trace at the point WithStack is called.
+// It is an alias to Wrap without options made for compatibility with github.com/pkg/errors.
+// If the error contains a stack trace then a new one will not be added to a chain.
+// If err is nil, WithStack returns nil.
+func WithStack(err error) error {
+	if err == nil || hasStack(err) {
+		return err
+	}
+
+	return &stacked{
+		wrapped: wrapped{wrapped: err},
+		stack:   stack{pcs: callers(0, 0)},
+	}
+}
+
+// AttachFields enriches err with fields without adding another stack trace, for example,
+// errors returned by third-party code. It is equivalent to Wrap with WithFields option:
+// if err already contains a stack trace, only fields are added, otherwise a stack trace
+// is recorded at the point AttachFields is called. If err is nil, AttachFields returns nil.
+func AttachFields(err error, fields ...Field) error {
+	return Wrap(err, WithFields(fields...), SkipCaller())
+}
+
+// WrapOnce works like Wrap, but annotates err only once for the given marker: if an error
+// in err's chain was already wrapped by WrapOnce with the same marker, err is returned as is.
+// It prevents duplicated fields when the same error passes through a layer several times,
+// for example, in retries or loops. The marker is not visible in logs and JSON.
+// An empty marker disables the check, so WrapOnce works as Wrap. If err is nil, WrapOnce returns nil.
+func WrapOnce(err error, marker string, options ...Option) error {
+	if err == nil {
+		return nil
+	}
+	if marker != "" && hasMarker(err, marker) {
+		return err
+	}
+
+	opts := newOptions(options...)
+	w := wrapped{wrapped: err, fields: opts.fields, marker: marker}
+	if opts.noStack || hasStack(err) {
+		return &w
+	}
 
 	return &stacked{
-		wrapped: &wrapped{wrapped: err, fields: opts.fields},
-		stack:   newStack(opts.skipCallers),
+		wrapped: w,
+		stack:   stack{pcs: opts.filterCallers(callers(opts.skipCallers, opts.stackDepth)), topFrames: opts.topFrames},
 	}
 }
 
+func hasMarker(err error, marker string) bool {
+	return !walkChain(err, func(e error) bool {
+		w, ok := e.(interface{ wrapMarker() string })
+		return !ok || w.wrapMarker() != marker
+	})
+}
+
+// IsWrapped reports whether err's chain contains an error created or wrapped by this package,
+// for example, by Errorf, Wrap or Wrapf. Such an error can be enriched by fields
+// and can be logged by Log function. Errors created by New are plain sentinel errors,
+// so they are not wrapped.
+//
+// A wrapped error does not necessarily contain a stack trace: it may be created with NoStack option.
+// Use HasStack to check whether wrapping of the error will record a new stack trace.
+func IsWrapped(err error) bool {
+	return isWrapper(err)
+}
+
+// HasStack reports whether err's chain contains a stack trace recorded by this package.
+// Every error with a stack trace is also wrapped, but not vice versa. If HasStack returns true,
+// then Wrap, Wrapf and WithStack will not record a new stack trace for the error.
+func HasStack(err error) bool {
+	return hasStack(err)
+}
+
 type wrapper interface {
 	isWrapper()
 }
@@ -174,15 +383,40 @@ func isWrapper(err error) bool {
 	return ok
 }
 
+// hasAnyStack reports whether any of the errors contains a stack trace recorded by this package.
+func hasAnyStack(errs []error) bool {
+	for _, err := range errs {
+		if hasStack(err) {
+			return true
+		}
+	}
+	return false
+}
+
+// hasStack reports whether err's chain contains a stack trace recorded by this package.
+// Branches of errors with Unwrap() []error method (like the ones created by the standard errors.Join)
+// are traversed too, so a stack trace anywhere in the tree prevents recording of a new one.
+func hasStack(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	_, ok := As[*stacked](err)
+
+	return ok
+}
+
 type wrapped struct {
 	wrapper
 	wrapped error
 	fields  []Field
+	marker  string // set by WrapOnce
 }
 
-func (e *wrapped) Fields() []Field { return e.fields }
-func (e *wrapped) Error() string   { return e.wrapped.Error() }
-func (e *wrapped) Unwrap() error   { return e.wrapped }
+func (e *wrapped) Fields() []Field    { return e.fields }
+func (e *wrapped) Error() string      { return e.wrapped.Error() }
+func (e *wrapped) Unwrap() error      { return e.wrapped }
+func (e *wrapped) wrapMarker() string { return e.marker }
 
 func (e *wrapped) LogFields(logger FieldLogger) {
 	for _, field := range e.fields {
@@ -195,16 +429,21 @@ func (e *wrapped) Format(s fmt.State, verb rune) {
 	case 'v':
 		io.WriteString(s, e.Error())
 		if s.Flag('+') {
-			fieldsWriter := &stringWriter{writer: s}
-			var err error
-			for err = e; err != nil; err = Unwrap(err) {
+			fieldsWriter := textWriter(s)
+			var err error = e
+			for depth := 0; err != nil && depth < MaxUnwrapDepth; err, depth = Unwrap(err), depth+1 {
+				if _, ok := err.(interface{ Unwrap() []error }); ok {
+					// fields of joined errors are formatted with each error
+					continue
+				}
 				if loggable, ok := err.(LoggableError); ok {
 					loggable.LogFields(fieldsWriter)
 				}
-				if tracer, ok := err.(stackTracer); ok {
+				if tracer, ok := err.(StackTracer); ok {
 					tracer.StackTrace().Format(s, verb)
 				}
 			}
+			formatJoinedErrors(s, e)
 		}
 	case 's', 'q':
 		io.WriteString(s, e.Error())
@@ -212,33 +451,25 @@ func (e *wrapped) Format(s fmt.State, verb rune) {
 }
 
 func (e *wrapped) MarshalJSON() ([]byte, error) {
-	data := mapWriter{"error": e.Error()}
-
-	var err error
-	for err = e; err != nil; err = Unwrap(err) {
-		if loggable, ok := err.(LoggableError); ok {
-			loggable.LogFields(data)
-		}
-		if tracer, ok := err.(stackTracer); ok {
-			data.SetStackTrace(tracer.StackTrace())
-		}
-	}
-
-	return json.Marshal(data)
+	return json.Marshal(errorData(e, defaultMarshalConfig))
 }
 
+// stacked embeds the wrapped error and the stack by value, so both are allocated at once.
 type stacked struct {
-	*wrapped
-	*stack
+	wrapped
+	stack
 }
 
+var _ StackTracer = (*stacked)(nil)
+
 func (e *stacked) Format(s fmt.State, verb rune) {
 	switch verb {
 	case 'v':
 		if s.Flag('+') {
 			io.WriteString(s, e.wrapped.Error())
-			e.wrapped.LogFields(&stringWriter{writer: s})
+			e.wrapped.LogFields(textWriter(s))
 			e.stack.Format(s, verb)
+			formatJoinedErrors(s, &e.wrapped)
 			return
 		}
 		fallthrough
@@ -250,22 +481,31 @@ func (e *stacked) Format(s fmt.State, verb rune) {
 }
 
 func (e *stacked) MarshalJSON() ([]byte, error) {
-	data := mapWriter{"error": e.Error()}
-	data.SetStackTrace(e.StackTrace())
-
-	var err error
-	for err = e; err != nil; err = Unwrap(err) {
-		if loggable, ok := err.(LoggableError); ok {
-			loggable.LogFields(data)
-		}
-	}
-
-	return json.Marshal(data)
+	return json.Marshal(errorData(e, defaultMarshalConfig))
 }
 
-func splitArgsAndOptions(argsAndOptions []interface{}) ([]interface{}, []Option) {
+// splitArgsAndOptions splits trailing options from formatting arguments. An Option that is
+// consumed by a verb of the format (like %v, %T or %p) or by an asterisk of a width or a precision
+// is the intended argument, so it and all the preceding values are left in arguments.
+// Options at positions of the %w verb or of missing verbs are applied as options,
+// because %w cannot format a function value.
+func splitArgsAndOptions(format string, argsAndOptions []interface{}) ([]interface{}, []Option) {
 	argsCount := len(argsAndOptions)
-	for i := argsCount - 1; i >= 0; i-- {
+	if argsCount == 0 {
+		return argsAndOptions, nil
+	}
+	if _, ok := argsAndOptions[argsCount-1].(Option); !ok {
+		return argsAndOptions, nil
+	}
+
+	// the last value consumed by the format and all the preceding values are arguments
+	minArgsCount := 0
+	scanVerbs(format, func(verb byte, argNum int) {
+		if verb != 'w' && argNum < argsCount && argNum >= minArgsCount {
+			minArgsCount = argNum + 1
+		}
+	})
+	for i := argsCount - 1; i >= minArgsCount; i-- {
 		if _, ok := argsAndOptions[i].(Option); ok {
 			argsCount--
 		} else {
@@ -283,106 +523,312 @@ func splitArgsAndOptions(argsAndOptions []interface{}) ([]interface{}, []Option)
 }
 
 func getArgErrors(message string, args []interface{}) []error {
-	indices := getErrorIndices(message)
-	errs := make([]error, 0, len(indices))
-
-	for _, i := range indices {
-		if err, ok := args[i].(error); ok {
+	var errs []error
+	scanVerbs(message, func(verb byte, argNum int) {
+		// options are already split from args, so an index out of range
+		// means a missing argument and must not point at an option
+		if verb != 'w' || argNum >= len(args) {
+			return
+		}
+		if err, ok := args[argNum].(error); ok {
 			errs = append(errs, err)
 		}
-	}
+	})
 
 	return errs
 }
 
-func getErrorIndices(message string) []int {
-	indices := make([]int, 0, 1)
-	isFormat := false
-
-	i := -1
-	for _, s := range message {
-		if isFormat {
-			if s != '%' {
-				i++
-				if s == 'w' {
-					indices = append(indices, i)
-				}
-			}
-			isFormat = false
-		} else if s == '%' {
-			isFormat = true
+// scanVerbs calls visit for every verb of the format with the index of its argument.
+// It follows the rules of the fmt package: flags, width and precision are skipped,
+// arguments consumed by '*' are visited with the '*' verb and explicit argument indexes
+// (like "%[2]w") are taken into account.
+func scanVerbs(message string, visit func(verb byte, argNum int)) {
+	argNum := 0
+	for i := 0; i < len(message); i++ {
+		if message[i] != '%' {
+			continue
+		}
+		i++
+		for i < len(message) && strings.IndexByte("+-# 0", message[i]) >= 0 {
+			i++
 		}
+		argNum, i = parseArgIndex(message, i, argNum)
+		argNum, i = skipWidth(message, i, argNum, visit)
+		if i < len(message) && message[i] == '.' {
+			argNum, i = parseArgIndex(message, i+1, argNum)
+			argNum, i = skipWidth(message, i, argNum, visit)
+		}
+		argNum, i = parseArgIndex(message, i, argNum)
+		if i >= len(message) {
+			break
+		}
+		if message[i] == '%' {
+			continue
+		}
+		visit(message[i], argNum)
+		argNum++
+	}
+}
+
+// parseArgIndex parses an explicit argument index (like "[2]") starting at i.
+// It returns the zero-based index of the next argument and the position after the index.
+func parseArgIndex(message string, i, argNum int) (int, int) {
+	if i >= len(message) || message[i] != '[' {
+		return argNum, i
+	}
+	end := strings.IndexByte(message[i:], ']')
+	if end < 0 {
+		return argNum, i
+	}
+	n, err := strconv.Atoi(message[i+1 : i+end])
+	if err != nil || n < 1 {
+		return argNum, i
 	}
 
-	return indices
+	return n - 1, i + end + 1
+}
+
+// skipWidth skips a width or a precision starting at i. An asterisk consumes an argument.
+func skipWidth(message string, i, argNum int, visit func(verb byte, argNum int)) (int, int) {
+	if i < len(message) && message[i] == '*' {
+		visit('*', argNum)
+		return argNum + 1, i + 1
+	}
+	for i < len(message) && message[i] >= '0' && message[i] <= '9' {
+		i++
+	}
+
+	return argNum, i
 }
 
 type mapWriter map[string]interface{}
 
-func (m mapWriter) SetBool(key string, value bool)              { m[key] = value }
-func (m mapWriter) SetInt(key string, value int)                { m[key] = value }
-func (m mapWriter) SetUint(key string, value uint)              { m[key] = value }
-func (m mapWriter) SetFloat(key string, value float64)          { m[key] = value }
-func (m mapWriter) SetString(key string, value string)          { m[key] = value }
-func (m mapWriter) SetStrings(key string, values []string)      { m[key] = values }
-func (m mapWriter) SetValue(key string, value interface{})      { m[key] = value }
-func (m mapWriter) SetTime(key string, value time.Time)         { m[key] = value }
-func (m mapWriter) SetDuration(key string, value time.Duration) { m[key] = value }
-func (m mapWriter) SetJSON(key string, value json.RawMessage)   { m[key] = value }
-func (m mapWriter) SetStackTrace(trace StackTrace)              { m["stackTrace"] = trace }
-
-type stringWriter struct {
-	writer io.Writer
+func (m mapWriter) SetBool(key string, value bool)                  { m[key] = value }
+func (m mapWriter) SetBools(key string, values []bool)              { m[key] = values }
+func (m mapWriter) SetInt(key string, value int)                    { m[key] = value }
+func (m mapWriter) SetInts(key string, values []int)                { m[key] = values }
+func (m mapWriter) SetInt32(key string, value int32)                { m[key] = value }
+func (m mapWriter) SetInt64(key string, value int64)                { m[key] = value }
+func (m mapWriter) SetUint(key string, value uint)                  { m[key] = value }
+func (m mapWriter) SetUint64(key string, value uint64)              { m[key] = value }
+func (m mapWriter) SetFloat(key string, value float64)              { m[key] = value }
+func (m mapWriter) SetFloats(key string, values []float64)          { m[key] = values }
+func (m mapWriter) SetString(key string, value string)              { m[key] = value }
+func (m mapWriter) SetStrings(key string, values []string)          { m[key] = values }
+func (m mapWriter) SetValue(key string, value interface{})          { m[key] = safeValue{value: value} }
+func (m mapWriter) SetTime(key string, value time.Time)             { m[key] = FormatTime(value) }
+func (m mapWriter) SetDuration(key string, value time.Duration)     { m[key] = value }
+func (m mapWriter) SetDurations(key string, values []time.Duration) { m[key] = values }
+func (m mapWriter) SetJSON(key string, value json.RawMessage)       { m[key] = value }
+
+func (m mapWriter) SetBytes(key string, value []byte, encoding BytesEncoding) {
+	m[key] = encoding.Encode(value)
+}
+
+// jsonWriter collects fields into the map to be marshaled into JSON. Nested errors
+// and stack traces are written according to the marshaling config.
+type jsonWriter struct {
+	mapWriter
+	config MarshalConfig
+}
+
+func (w jsonWriter) SetError(key string, value error) {
+	w.mapWriter[key] = errorValue(value, w.config)
 }
 
-func (s *stringWriter) SetBool(key string, value bool) {
-	if value {
-		io.WriteString(s.writer, "\n"+key+": true")
-	} else {
-		io.WriteString(s.writer, "\n"+key+": false")
+// setGroup writes the fields into the nested object. If the object already exists
+// (for example, the same group is set at several layers), the fields are merged into it.
+func (w jsonWriter) setGroup(key string, fields []Field) {
+	nested, ok := w.mapWriter[key].(mapWriter)
+	if !ok {
+		nested = mapWriter{}
 	}
+	group := jsonWriter{mapWriter: nested, config: w.config}
+	for _, field := range fields {
+		field.Set(group)
+	}
+	w.mapWriter[key] = group.mapWriter
 }
 
-func (s *stringWriter) SetInt(key string, value int) {
-	io.WriteString(s.writer, "\n"+key+": "+strconv.Itoa(value))
+func (w jsonWriter) SetErrors(key string, values []error) {
+	errs := make([]interface{}, len(values))
+	for i, value := range values {
+		errs[i] = errorValue(value, w.config)
+	}
+	w.mapWriter[key] = errs
 }
 
-func (s *stringWriter) SetUint(key string, value uint) {
-	io.WriteString(s.writer, "\n"+key+": "+strconv.FormatUint(uint64(value), 10))
+func (w jsonWriter) SetStackTrace(trace StackTrace) {
+	if w.config.IncludeStackTrace {
+		w.mapWriter[stackTraceJSONKey.Load().(string)] = stackTraceValue(trace, w.config)
+	}
 }
 
-func (s *stringWriter) SetFloat(key string, value float64) {
-	io.WriteString(s.writer, "\n"+key+": "+fmt.Sprintf("%f", value))
+// errorValue returns a value of the error field to be marshaled into JSON. Errors with fields
+// are converted into nested objects with fields and a stack trace, and other errors are converted
+// into the error message.
+func errorValue(err error, config MarshalConfig) interface{} {
+	if err == nil {
+		return nil
+	}
+	if _, ok := As[LoggableError](err); !ok {
+		return err.Error()
+	}
+
+	return errorData(err, config)
 }
 
-func (s *stringWriter) SetString(key string, value string) {
-	io.WriteString(s.writer, "\n"+key+": "+value)
+// errorData collects the message, fields and stack trace of err's chain to be marshaled into JSON.
+// Joined errors are collected recursively into the nested "errors" array.
+func errorData(err error, config MarshalConfig) mapWriter {
+	return errorDataAt(err, config, 0, &unwrapWalk{})
 }
 
-func (s *stringWriter) SetStrings(key string, values []string) {
-	io.WriteString(s.writer, "\n"+key+": ")
-	for i, value := range values {
-		if i > 0 {
-			io.WriteString(s.writer, ", ")
+func errorDataAt(err error, config MarshalConfig, depth int, walk *unwrapWalk) mapWriter {
+	data := jsonWriter{
+		mapWriter: mapWriter{messageJSONKey.Load().(string): err.Error()},
+		config:    config,
+	}
+	for e := err; e != nil && walk.next(depth); e, depth = Unwrap(e), depth+1 {
+		if joined, ok := e.(interface{ Unwrap() []error }); ok {
+			if walk.enter(e) {
+				errs := joined.Unwrap()
+				nested := make([]mapWriter, len(errs))
+				for i, joinedErr := range errs {
+					nested[i] = errorDataAt(joinedErr, config, depth+1, walk)
+				}
+				data.mapWriter["errors"] = nested
+			}
+			continue
 		}
-		io.WriteString(s.writer, value)
+		if loggable, ok := e.(LoggableError); ok {
+			loggable.LogFields(data)
+		}
+		if tracer, ok := e.(StackTracer); ok {
+			data.SetStackTrace(limitedStackTrace(tracer))
+		}
+	}
+
+	return data.mapWriter
+}
+
+// keyValueLogger passes every field to the function as a key-value pair with a raw value.
+// Stack trace is ignored.
+type keyValueLogger func(key string, value interface{})
+
+func (f keyValueLogger) SetBool(key string, value bool)                  { f(key, value) }
+func (f keyValueLogger) SetBools(key string, values []bool)              { f(key, values) }
+func (f keyValueLogger) SetInt(key string, value int)                    { f(key, value) }
+func (f keyValueLogger) SetInts(key string, values []int)                { f(key, values) }
+func (f keyValueLogger) SetInt32(key string, value int32)                { f(key, value) }
+func (f keyValueLogger) SetInt64(key string, value int64)                { f(key, value) }
+func (f keyValueLogger) SetUint(key string, value uint)                  { f(key, value) }
+func (f keyValueLogger) SetUint64(key string, value uint64)              { f(key, value) }
+func (f keyValueLogger) SetFloat(key string, value float64)              { f(key, value) }
+func (f keyValueLogger) SetFloats(key string, values []float64)          { f(key, values) }
+func (f keyValueLogger) SetString(key string, value string)              { f(key, value) }
+func (f keyValueLogger) SetStrings(key string, values []string)          { f(key, values) }
+func (f keyValueLogger) SetValue(key string, value interface{})          { f(key, value) }
+func (f keyValueLogger) SetTime(key string, value time.Time)             { f(key, value) }
+func (f keyValueLogger) SetDuration(key string, value time.Duration)     { f(key, value) }
+func (f keyValueLogger) SetDurations(key string, values []time.Duration) { f(key, values) }
+func (f keyValueLogger) SetJSON(key string, value json.RawMessage)       { f(key, value) }
+func (f keyValueLogger) SetError(key string, value error)                { f(key, value) }
+func (f keyValueLogger) SetErrors(key string, values []error)            { f(key, values) }
+func (f keyValueLogger) SetStackTrace(trace StackTrace)                  {}
+
+func (f keyValueLogger) SetBytes(key string, value []byte, encoding BytesEncoding) {
+	f(key, value)
+}
+
+// textLogger passes every field to the function as a key-value pair with a value
+// rendered as a text string. Slices are rendered as comma-separated lists.
+// Stack trace is ignored.
+type textLogger func(key string, value string)
+
+// textWriter returns the logger that writes every field into a new line as "key: value".
+func textWriter(writer io.Writer) indentedTextWriter {
+	return newIndentedTextWriter(writer, "")
+}
+
+// indentedTextWriter writes every field into a new line as "key: value" prefixed by the indent.
+// Fields of a group are written under the line with the key of the group with a deeper indent.
+type indentedTextWriter struct {
+	textLogger
+	writer io.Writer
+	indent string
+}
+
+func newIndentedTextWriter(writer io.Writer, indent string) indentedTextWriter {
+	return indentedTextWriter{
+		textLogger: func(key string, value string) {
+			io.WriteString(writer, "\n"+indent+key+": "+value)
+		},
+		writer: writer,
+		indent: indent,
 	}
 }
 
-func (s *stringWriter) SetValue(key string, value interface{}) {
-	io.WriteString(s.writer, "\n"+key+": "+fmt.Sprintf("%v", value))
+func (w indentedTextWriter) setGroup(key string, fields []Field) {
+	io.WriteString(w.writer, "\n"+w.indent+key+":")
+	group := newIndentedTextWriter(w.writer, w.indent+"  ")
+	for _, field := range fields {
+		field.Set(group)
+	}
 }
 
-func (s *stringWriter) SetTime(key string, value time.Time) {
-	io.WriteString(s.writer, "\n"+key+": "+value.String())
+func (l textLogger) SetBool(key string, value bool) { l(key, strconv.FormatBool(value)) }
+func (l textLogger) SetBools(key string, values []bool) {
+	l(key, joinValues(values, strconv.FormatBool))
 }
+func (l textLogger) SetInt(key string, value int)       { l(key, strconv.Itoa(value)) }
+func (l textLogger) SetInts(key string, values []int)   { l(key, joinValues(values, strconv.Itoa)) }
+func (l textLogger) SetInt32(key string, value int32)   { l(key, strconv.FormatInt(int64(value), 10)) }
+func (l textLogger) SetInt64(key string, value int64)   { l(key, strconv.FormatInt(value, 10)) }
+func (l textLogger) SetUint(key string, value uint)     { l(key, strconv.FormatUint(uint64(value), 10)) }
+func (l textLogger) SetUint64(key string, value uint64) { l(key, strconv.FormatUint(value, 10)) }
+func (l textLogger) SetFloat(key string, value float64) { l(key, formatFloat(value)) }
+func (l textLogger) SetFloats(key string, values []float64) {
+	l(key, joinValues(values, formatFloat))
+}
+func (l textLogger) SetString(key string, value string)          { l(key, value) }
+func (l textLogger) SetStrings(key string, values []string)      { l(key, strings.Join(values, ", ")) }
+func (l textLogger) SetValue(key string, value interface{})      { l(key, formatValue(value)) }
+func (l textLogger) SetTime(key string, value time.Time)         { l(key, FormatTime(value)) }
+func (l textLogger) SetDuration(key string, value time.Duration) { l(key, value.String()) }
+func (l textLogger) SetDurations(key string, values []time.Duration) {
+	l(key, joinValues(values, time.Duration.String))
+}
+func (l textLogger) SetJSON(key string, value json.RawMessage) { l(key, string(value)) }
+func (l textLogger) SetBytes(key string, value []byte, encoding BytesEncoding) {
+	l(key, encoding.Encode(value))
+}
+func (l textLogger) SetError(key string, value error) { l(key, errorMessage(value)) }
+func (l textLogger) SetErrors(key string, values []error) {
+	l(key, joinValues(values, errorMessage))
+}
+func (l textLogger) SetStackTrace(trace StackTrace) {}
 
-func (s *stringWriter) SetDuration(key string, value time.Duration) {
-	io.WriteString(s.writer, "\n"+key+": "+value.String())
+// joinValues renders every value by the format function and joins them by a comma.
+func joinValues[T any](values []T, format func(T) string) string {
+	var s strings.Builder
+	for i, value := range values {
+		if i > 0 {
+			s.WriteString(", ")
+		}
+		s.WriteString(format(value))
+	}
+	return s.String()
 }
 
-func (s *stringWriter) SetJSON(key string, value json.RawMessage) {
-	io.WriteString(s.writer, "\n"+key+": "+string(value))
+// formatFloat formats a float in the shortest representation, for example "0.5" or "1e+21".
+func formatFloat(value float64) string {
+	return strconv.FormatFloat(value, 'g', -1, 64)
 }
 
-func (s *stringWriter) SetStackTrace(trace StackTrace) {}
+func errorMessage(err error) string {
+	if err == nil {
+		return "<nil>"
+	}
+	return err.Error()
+}
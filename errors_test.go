@@ -185,8 +185,8 @@ func TestStackTrace(t *testing.T) {
 			name: "Errorf() with multiple errors",
 			err: errors.Errorf(
 				"first: %w; second: %w",
-				errors.Errorf("ooh"),
-				errors.Errorf("ooh"),
+				errors.New("ooh"),
+				errors.New("ooh"),
 			),
 			want: []string{
 				"github.com/muonsoft/errors_test.TestStackTrace\n" +
@@ -197,7 +197,7 @@ func TestStackTrace(t *testing.T) {
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
 			assertSingleStack(t, test.err)
-			stacked, ok := errors.As[StackTracer](test.err)
+			stacked, ok := errors.As[errors.StackTracer](test.err)
 			if !ok {
 				t.Fatalf("expected %#v to implement errors.StackTracer", test.err)
 			}
@@ -236,6 +236,11 @@ func TestFields(t *testing.T) {
 			err:      errors.Wrap(errors.Errorf("error"), errors.Bool("key", true)),
 			expected: true,
 		},
+		{
+			name:     "bools",
+			err:      errors.Wrap(errors.Errorf("error"), errors.Bools("key", []bool{true, false})),
+			expected: []bool{true, false},
+		},
 		{
 			name:     "int",
 			err:      errors.Wrap(errors.Errorf("error"), errors.Int("key", 1)),
@@ -246,11 +251,36 @@ func TestFields(t *testing.T) {
 			err:      errors.Wrap(errors.Errorf("error"), errors.Uint("key", 1)),
 			expected: uint(1),
 		},
+		{
+			name:     "ints",
+			err:      errors.Wrap(errors.Errorf("error"), errors.Ints("key", []int{1, 2})),
+			expected: []int{1, 2},
+		},
+		{
+			name:     "int32",
+			err:      errors.Wrap(errors.Errorf("error"), errors.Int32("key", 1<<31-1)),
+			expected: int32(1<<31 - 1),
+		},
+		{
+			name:     "int64",
+			err:      errors.Wrap(errors.Errorf("error"), errors.Int64("key", 1<<63-1)),
+			expected: int64(1<<63 - 1),
+		},
+		{
+			name:     "uint64",
+			err:      errors.Wrap(errors.Errorf("error"), errors.Uint64("key", 1<<64-1)),
+			expected: uint64(1<<64 - 1),
+		},
 		{
 			name:     "float",
 			err:      errors.Wrap(errors.Errorf("error"), errors.Float("key", 1.0)),
 			expected: 1.0,
 		},
+		{
+			name:     "floats",
+			err:      errors.Wrap(errors.Errorf("error"), errors.Floats("key", []float64{1.5, 2})),
+			expected: []float64{1.5, 2},
+		},
 		{
 			name:     "string",
 			err:      errors.Wrap(errors.Errorf("error"), errors.String("key", "value")),
@@ -261,6 +291,26 @@ func TestFields(t *testing.T) {
 			err:      errors.Wrap(errors.Errorf("error"), errors.Stringer("key", stringer{s: "value"})),
 			expected: "value",
 		},
+		{
+			name:     "bytes",
+			err:      errors.Wrap(errors.Errorf("error"), errors.Bytes("key", []byte{0x01, 0xab, 0xff})),
+			expected: "01abff",
+		},
+		{
+			name:     "bytes base64",
+			err:      errors.Wrap(errors.Errorf("error"), errors.BytesBase64("key", []byte("value"))),
+			expected: "dmFsdWU=",
+		},
+		{
+			name:     "formatter interface",
+			err:      errors.Wrap(errors.Errorf("error"), errors.Formatter("key", formatter{s: "value"})),
+			expected: "formatted(value)",
+		},
+		{
+			name:     "go stringer interface",
+			err:      errors.Wrap(errors.Errorf("error"), errors.GoStringer("key", goStringer{s: "value"})),
+			expected: `goStringer{s: "value"}`,
+		},
 		{
 			name:     "strings",
 			err:      errors.Wrap(errors.Errorf("error"), errors.Strings("key", []string{"value"})),
@@ -284,11 +334,26 @@ func TestFields(t *testing.T) {
 			err:      errors.Wrap(errors.Errorf("error"), errors.Duration("key", time.Hour)),
 			expected: time.Hour,
 		},
+		{
+			name:     "durations",
+			err:      errors.Wrap(errors.Errorf("error"), errors.Durations("key", []time.Duration{time.Second})),
+			expected: []time.Duration{time.Second},
+		},
 		{
 			name:     "JSON",
 			err:      errors.Wrap(errors.Errorf("error"), errors.JSON("key", []byte(`{"key":"value"}`))),
 			expected: json.RawMessage(`{"key":"value"}`),
 		},
+		{
+			name:     "error",
+			err:      errors.Wrap(errors.Errorf("error"), errors.Error("key", errTest)),
+			expected: errTest,
+		},
+		{
+			name:     "errors",
+			err:      errors.Wrap(errors.Errorf("error"), errors.Errors("key", []error{errTest, nil})),
+			expected: []error{errTest, nil},
+		},
 		{
 			name:     "wrap with stack",
 			err:      errors.Wrap(errors.New("error"), errors.String("key", "value")),
@@ -658,3 +723,522 @@ type stringer struct {
 func (s stringer) String() string {
 	return s.s
 }
+
+func TestWrapf(t *testing.T) {
+	tests := []struct {
+		name        string
+		err         error
+		wantMessage string
+	}{
+		{
+			name:        "sentinel error",
+			err:         errors.Wrapf(errTest, "find product %d", 123),
+			wantMessage: "find product 123: test error",
+		},
+		{
+			name:        "error with stack",
+			err:         errors.Wrapf(errors.Wrap(errTest), "find product %d", 123, errors.String("key", "value")),
+			wantMessage: "find product 123: test error",
+		},
+		{
+			name:        "error with options",
+			err:         errors.Wrapf(errTest, "find product", errors.String("key", "value")),
+			wantMessage: "find product: test error",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if test.err.Error() != test.wantMessage {
+				t.Errorf("want message %q, got %q", test.wantMessage, test.err.Error())
+			}
+			if !errors.Is(test.err, errTest) {
+				t.Errorf("want %#v to wrap %#v", test.err, errTest)
+			}
+			assertSingleStack(t, test.err)
+		})
+	}
+}
+
+func TestWrapf_nil(t *testing.T) {
+	if err := errors.Wrapf(nil, "ooh"); err != nil {
+		t.Errorf("want nil error, got %v", err)
+	}
+}
+
+func TestWrapf_fields(t *testing.T) {
+	err := errors.Wrapf(errors.Errorf("ooh"), "find product", errors.String("key", "value"))
+
+	if value, _ := errors.GetField(err, "key"); value != "value" {
+		t.Errorf(`want %#v to have field "key"`, err)
+	}
+}
+
+func TestNoStack(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+	}{
+		{name: "Wrap", err: errors.Wrap(errTest, errors.NoStack(), errors.String("key", "value"))},
+		{name: "Errorf", err: errors.Errorf("ooh", errors.NoStack(), errors.String("key", "value"))},
+		{name: "Wrapf", err: errors.Wrapf(errTest, "ooh", errors.NoStack(), errors.String("key", "value"))},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if _, ok := errors.As[errors.StackTracer](test.err); ok {
+				t.Errorf("want %#v not to have a stack trace", test.err)
+			}
+			logger := errorstest.NewLogger()
+			errors.Log(test.err, logger)
+			logger.AssertField(t, "key", "value")
+
+			err := errors.Wrap(test.err)
+			assertSingleStack(t, err)
+			err = errors.Errorf("wrapped: %w", test.err)
+			assertSingleStack(t, err)
+		})
+	}
+}
+
+func TestErrorf_multipleWrappedErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+	}{
+		{
+			name: "first error with stack",
+			err:  errors.Errorf("%w and %w", errors.Errorf("ooh"), errTest),
+		},
+		{
+			name: "second error with stack",
+			err:  errors.Errorf("%w and %w", errTest, errors.Wrap(errors.New("ooh"))),
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			stacks := 0
+			for _, err := range errors.Chain(test.err) {
+				if _, ok := err.(errors.StackTracer); ok {
+					stacks++
+				}
+			}
+			if stacks != 1 {
+				t.Errorf("want exactly one stack in chain of %#v, got %d", test.err, stacks)
+			}
+			if !errors.Is(test.err, errTest) {
+				t.Errorf("want %#v to wrap %#v", test.err, errTest)
+			}
+		})
+	}
+}
+
+func TestErrorf_explicitArgumentIndex(t *testing.T) {
+	tests := []struct {
+		name        string
+		err         error
+		wantMessage string
+	}{
+		{
+			name:        "indexed wrapped error with stack",
+			err:         errors.Errorf("%[2]w: %[1]s", "find product", errors.Wrap(errTest)),
+			wantMessage: "test error: find product",
+		},
+		{
+			name:        "indexed wrapped error without stack",
+			err:         errors.Errorf("%[2]w: %[1]s", "find product", errTest),
+			wantMessage: "test error: find product",
+		},
+		{
+			name:        "width from argument",
+			err:         errors.Errorf("%*d: %w", 5, 123, errors.Wrap(errTest)),
+			wantMessage: "  123: test error",
+		},
+		{
+			name:        "flags and precision",
+			err:         errors.Errorf("%+.2f%%: %w", 1.5, errors.Wrap(errTest)),
+			wantMessage: "+1.50%: test error",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if test.err.Error() != test.wantMessage {
+				t.Errorf("want message %q, got %q", test.wantMessage, test.err.Error())
+			}
+			if !errors.Is(test.err, errTest) {
+				t.Errorf("want %#v to wrap %#v", test.err, errTest)
+			}
+			assertSingleStack(t, test.err)
+		})
+	}
+}
+
+func TestErrorf_errorFollowedByOptions(t *testing.T) {
+	err := errors.Errorf(
+		"find product: %w",
+		errors.Wrap(errTest),
+		errors.String("key", "value"),
+		errors.Int("id", 123),
+		errors.Bool("ok", true),
+	)
+
+	if err.Error() != "find product: test error" {
+		t.Errorf(`want message "find product: test error", got %q`, err.Error())
+	}
+	assertSingleStack(t, err)
+	logger := errorstest.NewLogger()
+	errors.Log(err, logger)
+	logger.AssertField(t, "key", "value")
+	logger.AssertField(t, "id", 123)
+	logger.AssertField(t, "ok", true)
+}
+
+func TestErrorf_missingWrappedError(t *testing.T) {
+	err := errors.Errorf("find product: %w", errors.String("key", "value"))
+
+	if err.Error() != "find product: %!w(MISSING)" {
+		t.Errorf(`want message with missing argument, got %q`, err.Error())
+	}
+	assertSingleStack(t, err)
+	logger := errorstest.NewLogger()
+	errors.Log(err, logger)
+	logger.AssertField(t, "key", "value")
+}
+
+func TestWithStack(t *testing.T) {
+	if errors.WithStack(nil) != nil {
+		t.Error("want nil for nil error")
+	}
+
+	err := errors.WithStack(errTest)
+
+	assertSingleStack(t, err)
+	if !errors.Is(err, errTest) {
+		t.Errorf("want %#v to wrap %#v", err, errTest)
+	}
+	stacked, _ := errors.As[errors.StackTracer](err)
+	if name := stacked.StackTrace()[0].Name(); name != "github.com/muonsoft/errors_test.TestWithStack" {
+		t.Errorf("want stack trace to point to the test function, got %s", name)
+	}
+	if wrapped := errors.WithStack(err); wrapped != err {
+		t.Errorf("want error with stack to be returned as is, got %#v", wrapped)
+	}
+	if wrapped := errors.Wrap(err); wrapped != errors.WithStack(err) {
+		t.Errorf("want WithStack to behave as Wrap")
+	}
+}
+
+func TestAsAll(t *testing.T) {
+	err := errors.Wrap(errors.Join(
+		errors.Errorf("first: %w", errorT{s: "one"}),
+		errors.New("plain"),
+		fmt.Errorf("second: %w", errorT{s: "two"}),
+	))
+
+	got := errors.AsAll[errorT](err)
+
+	want := []errorT{{s: "one"}, {s: "two"}}
+	if len(got) != len(want) {
+		t.Fatalf("want %d matches, got %d: %v", len(want), len(got), got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("want match #%d to be %v, got %v", i, want[i], got[i])
+		}
+	}
+}
+
+func TestAsAll_noMatches(t *testing.T) {
+	if got := errors.AsAll[errorT](errors.New("ooh")); got != nil {
+		t.Errorf("want nil, got %v", got)
+	}
+	if got := errors.AsAll[errorT](nil); got != nil {
+		t.Errorf("want nil, got %v", got)
+	}
+}
+
+func TestIsAnyAndIsAll(t *testing.T) {
+	first := errors.New("first")
+	second := errors.New("second")
+	third := errors.New("third")
+	err := errors.Join(errors.Wrap(first), fmt.Errorf("wrapped: %w", second))
+
+	tests := []struct {
+		name    string
+		targets []error
+		wantAny bool
+		wantAll bool
+	}{
+		{name: "contained sentinels", targets: []error{first, second}, wantAny: true, wantAll: true},
+		{name: "all sentinels", targets: []error{first, second, third}, wantAny: true, wantAll: false},
+		{name: "missing sentinel", targets: []error{third}, wantAny: false, wantAll: false},
+		{name: "no sentinels", targets: nil, wantAny: false, wantAll: true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := errors.IsAny(err, test.targets...); got != test.wantAny {
+				t.Errorf("want IsAny to be %t, got %t", test.wantAny, got)
+			}
+			if got := errors.IsAll(err, test.targets...); got != test.wantAll {
+				t.Errorf("want IsAll to be %t, got %t", test.wantAll, got)
+			}
+		})
+	}
+}
+
+func TestAttachFields(t *testing.T) {
+	tests := []struct {
+		name      string
+		err       error
+		wantStack string
+	}{
+		{
+			name:      "error with stack",
+			err:       errors.Errorf("ooh"),
+			wantStack: "github.com/muonsoft/errors_test.TestAttachFields",
+		},
+		{
+			name:      "standard error chain",
+			err:       fmt.Errorf("foreign: %w", errTest),
+			wantStack: "github.com/muonsoft/errors_test.TestAttachFields.func1",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := errors.AttachFields(test.err, errors.StringField{Key: "key", Value: "value"})
+
+			assertSingleStack(t, err)
+			logger := errorstest.NewLogger()
+			errors.Log(err, logger)
+			logger.AssertField(t, "key", "value")
+			if name := logger.StackTrace[0].Name(); name != test.wantStack {
+				t.Errorf("want stack trace to start at %s, got %s", test.wantStack, name)
+			}
+		})
+	}
+}
+
+func TestAttachFields_nil(t *testing.T) {
+	if err := errors.AttachFields(nil, errors.StringField{Key: "key", Value: "value"}); err != nil {
+		t.Errorf("want nil, got %#v", err)
+	}
+}
+
+type formatter struct {
+	s string
+}
+
+func (f formatter) Format(s fmt.State, verb rune) {
+	fmt.Fprintf(s, "formatted(%s)", f.s)
+}
+
+type goStringer struct {
+	s string
+}
+
+func (g goStringer) GoString() string {
+	return fmt.Sprintf("goStringer{s: %q}", g.s)
+}
+
+func TestWrap_stdlibJoinWithStack(t *testing.T) {
+	joined := stderrors.Join(errors.New("first"), errors.Errorf("second"))
+	tests := []struct {
+		name string
+		err  error
+	}{
+		{name: "Wrap", err: errors.Wrap(joined)},
+		{name: "Wrap with options", err: errors.Wrap(joined, errors.String("key", "value"))},
+		{name: "Wrapf", err: errors.Wrapf(joined, "wrapped")},
+		{name: "WithStack", err: errors.WithStack(joined)},
+		{name: "Errorf", err: errors.Errorf("wrapped: %w", joined)},
+		{name: "nested", err: errors.Wrap(fmt.Errorf("wrapped: %w", joined))},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			// the only stack is in the branch of the joined error
+			errorstest.AssertNoStack(t, test.err)
+			if !errors.Is(test.err, joined) {
+				t.Errorf("want %#v to wrap the joined error", test.err)
+			}
+		})
+	}
+}
+
+func TestWrap_stdlibJoinWithoutStack(t *testing.T) {
+	err := errors.Wrap(stderrors.Join(errors.New("first"), errors.New("second")))
+
+	assertSingleStack(t, err)
+}
+
+func TestIsWrappedAndHasStack(t *testing.T) {
+	tests := []struct {
+		name      string
+		err       error
+		isWrapped bool
+		hasStack  bool
+	}{
+		{name: "nil", err: nil},
+		{name: "plain", err: errors.New("ooh")},
+		{name: "foreign", err: fmt.Errorf("wrapped: %w", errors.New("ooh"))},
+		{name: "wrapped", err: errors.Wrap(errors.New("ooh"), errors.NoStack()), isWrapped: true},
+		{name: "stacked", err: errors.Errorf("ooh"), isWrapped: true, hasStack: true},
+		{
+			name:      "stacked under foreign",
+			err:       fmt.Errorf("wrapped: %w", errors.Wrap(errors.New("ooh"))),
+			isWrapped: true,
+			hasStack:  true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := errors.IsWrapped(test.err); got != test.isWrapped {
+				t.Errorf("IsWrapped() = %t, want %t", got, test.isWrapped)
+			}
+			if got := errors.HasStack(test.err); got != test.hasStack {
+				t.Errorf("HasStack() = %t, want %t", got, test.hasStack)
+			}
+		})
+	}
+}
+
+//go:noinline
+func newEntityError(id int) error {
+	return errors.ErrorfSkip(1, "entity %d not found", id)
+}
+
+func TestErrorfSkip(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+	}{
+		{name: "skip 0", err: errors.ErrorfSkip(0, "entity %d not found", 1)},
+		{name: "skip 1", err: newEntityError(1)},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := test.err.Error(); got != "entity 1 not found" {
+				t.Errorf(`want message "entity 1 not found", got "%s"`, got)
+			}
+			trace, ok := errors.GetStackTrace(test.err)
+			if !ok {
+				t.Fatalf("want %#v to have a stack trace", test.err)
+			}
+			if name := trace[0].Name(); name != "github.com/muonsoft/errors_test.TestErrorfSkip" {
+				t.Errorf("want stack trace to start at the test function, got %s", name)
+			}
+		})
+	}
+}
+
+func TestErrorfSkip_wrappedStack(t *testing.T) {
+	err := errors.ErrorfSkip(0, "wrapped: %w", errors.Errorf("ooh"))
+
+	assertSingleStack(t, err)
+}
+
+func TestErrorf_moreVerbsThanArguments(t *testing.T) {
+	err := errors.Errorf("find %s by id %d: %w", "product", 123, errors.String("key", "value"))
+
+	if err.Error() != "find product by id 123: %!w(MISSING)" {
+		t.Errorf(`want message with missing argument, got %q`, err.Error())
+	}
+	logger := errorstest.NewLogger()
+	errors.Log(err, logger)
+	logger.AssertField(t, "key", "value")
+}
+
+func TestErrorf_optionAsValueArgument(t *testing.T) {
+	option := errors.String("formatted", "value")
+
+	err := errors.Errorf("option %v", option, errors.Int("id", 123))
+
+	if want := fmt.Sprintf("option %v", option); err.Error() != want {
+		t.Errorf(`want message %q, got %q`, want, err.Error())
+	}
+	logger := errorstest.NewLogger()
+	errors.Log(err, logger)
+	logger.AssertField(t, "id", 123)
+	if _, exists := logger.Fields["formatted"]; exists {
+		t.Errorf("want option formatted by %%v not to be applied")
+	}
+}
+
+var errSentinel = errors.NewSentinel("sentinel")
+
+func TestNewSentinel(t *testing.T) {
+	err := errors.Wrap(errSentinel, errors.String("key", "value"))
+
+	if !errors.Is(err, errSentinel) {
+		t.Errorf("want %#v to match the sentinel", err)
+	}
+	if errors.HasStack(errSentinel) {
+		t.Error("want sentinel not to have a stack trace")
+	}
+	assertSingleStack(t, err)
+	logger := errorstest.NewLogger()
+	errors.Log(err, logger)
+	logger.AssertField(t, "key", "value")
+	assertFormatRegexp(t, logger.Fields["definedAt"], "%s", "^.+/errors/errors_test.go:1164$")
+}
+
+func TestWrapOnce(t *testing.T) {
+	err := errors.Errorf("ooh")
+	for i := 0; i < 3; i++ {
+		err = errors.WrapOnce(err, "retry", errors.String("layer", "retry"))
+		err = errors.Wrap(err, errors.Int("attempt", i))
+	}
+	err = errors.WrapOnce(err, "handler", errors.String("layer", "handler"))
+
+	layers := 0
+	for _, field := range errors.Fields(err) {
+		if f, ok := field.(errors.StringField); ok && f.Key == "layer" {
+			layers++
+		}
+	}
+	if layers != 2 {
+		t.Errorf(`want field "layer" to be set once per marker, got %d fields`, layers)
+	}
+	if err.Error() != "ooh" {
+		t.Errorf(`want message "ooh", got %q`, err.Error())
+	}
+	assertSingleStack(t, err)
+}
+
+func TestWrapOnce_stack(t *testing.T) {
+	err := errors.WrapOnce(errors.New("ooh"), "marker")
+
+	assertSingleStack(t, err)
+	if wrapped := errors.WrapOnce(err, "marker", errors.String("key", "value")); wrapped != err {
+		t.Errorf("want error wrapped with the same marker to be returned as is, got %#v", wrapped)
+	}
+	if errors.WrapOnce(nil, "marker") != nil {
+		t.Error("want nil for nil error")
+	}
+}
+
+func TestErrorf_optionAsArgumentOfAnyVerb(t *testing.T) {
+	option := errors.String("formatted", "value")
+	tests := []struct {
+		format string
+		args   []interface{}
+	}{
+		{format: "option %T", args: []interface{}{option}},
+		{format: "option %p", args: []interface{}{option}},
+		{format: "option %d", args: []interface{}{option}},
+		{format: "option %s", args: []interface{}{option}},
+		{format: "option %*v", args: []interface{}{option, "value"}},
+	}
+	for _, test := range tests {
+		t.Run(test.format, func(t *testing.T) {
+			err := errors.Errorf(test.format, append(test.args, errors.Int("id", 123))...)
+
+			if want := fmt.Sprintf(test.format, test.args...); err.Error() != want {
+				t.Errorf(`want message %q, got %q`, want, err.Error())
+			}
+			logger := errorstest.NewLogger()
+			errors.Log(err, logger)
+			logger.AssertField(t, "id", 123)
+			if _, exists := logger.Fields["formatted"]; exists {
+				t.Errorf("want option formatted by the verb not to be applied")
+			}
+		})
+	}
+}
@@ -2,6 +2,7 @@ package errorstest
 
 import (
 	"encoding/json"
+	"fmt"
 	"reflect"
 	"regexp"
 	"testing"
@@ -28,18 +29,31 @@ func NewLogger() *Logger {
 	return &Logger{Fields: make(map[string]interface{})}
 }
 
-func (m *Logger) SetBool(key string, value bool)              { m.Fields[key] = value }
-func (m *Logger) SetInt(key string, value int)                { m.Fields[key] = value }
-func (m *Logger) SetUint(key string, value uint)              { m.Fields[key] = value }
-func (m *Logger) SetFloat(key string, value float64)          { m.Fields[key] = value }
-func (m *Logger) SetString(key string, value string)          { m.Fields[key] = value }
-func (m *Logger) SetStrings(key string, values []string)      { m.Fields[key] = values }
-func (m *Logger) SetValue(key string, value interface{})      { m.Fields[key] = value }
-func (m *Logger) SetTime(key string, value time.Time)         { m.Fields[key] = value }
-func (m *Logger) SetDuration(key string, value time.Duration) { m.Fields[key] = value }
-func (m *Logger) SetJSON(key string, value json.RawMessage)   { m.Fields[key] = value }
-func (m *Logger) SetStackTrace(trace errors.StackTrace)       { m.StackTrace = trace }
-func (m *Logger) Log(message string)                          { m.Message = message }
+func (m *Logger) SetBool(key string, value bool)                  { m.Fields[key] = value }
+func (m *Logger) SetBools(key string, values []bool)              { m.Fields[key] = values }
+func (m *Logger) SetInt(key string, value int)                    { m.Fields[key] = value }
+func (m *Logger) SetInts(key string, values []int)                { m.Fields[key] = values }
+func (m *Logger) SetInt32(key string, value int32)                { m.Fields[key] = value }
+func (m *Logger) SetInt64(key string, value int64)                { m.Fields[key] = value }
+func (m *Logger) SetUint(key string, value uint)                  { m.Fields[key] = value }
+func (m *Logger) SetUint64(key string, value uint64)              { m.Fields[key] = value }
+func (m *Logger) SetFloat(key string, value float64)              { m.Fields[key] = value }
+func (m *Logger) SetFloats(key string, values []float64)          { m.Fields[key] = values }
+func (m *Logger) SetString(key string, value string)              { m.Fields[key] = value }
+func (m *Logger) SetStrings(key string, values []string)          { m.Fields[key] = values }
+func (m *Logger) SetValue(key string, value interface{})          { m.Fields[key] = value }
+func (m *Logger) SetTime(key string, value time.Time)             { m.Fields[key] = value }
+func (m *Logger) SetDuration(key string, value time.Duration)     { m.Fields[key] = value }
+func (m *Logger) SetDurations(key string, values []time.Duration) { m.Fields[key] = values }
+func (m *Logger) SetJSON(key string, value json.RawMessage)       { m.Fields[key] = value }
+func (m *Logger) SetError(key string, value error)                { m.Fields[key] = value }
+func (m *Logger) SetErrors(key string, values []error)            { m.Fields[key] = values }
+func (m *Logger) SetStackTrace(trace errors.StackTrace)           { m.StackTrace = trace }
+func (m *Logger) Log(message string)                              { m.Message = message }
+
+func (m *Logger) SetBytes(key string, value []byte, encoding errors.BytesEncoding) {
+	m.Fields[key] = encoding.Encode(value)
+}
 
 func (m *Logger) AssertMessage(t *testing.T, expected string) {
 	t.Helper()
@@ -72,24 +86,141 @@ func (m *Logger) AssertStackTrace(t *testing.T, want StackTrace) {
 	}
 
 	for i, w := range want {
-		match, err := regexp.MatchString(w.Function, got[i].Name())
+		diff, err := frameDiff(i+1, w, got[i])
 		if err != nil {
 			t.Fatal(err)
 		}
-		if !match {
-			t.Errorf("unexpected stack: function on line %d:\n got: %q\nwant: %q", i+1, got[i].Name(), w.Function)
+		for _, d := range diff {
+			t.Errorf("unexpected stack: %s", d)
 		}
+	}
+}
 
-		match, err = regexp.MatchString(w.File, got[i].File())
-		if err != nil {
-			t.Fatal(err)
-		}
-		if !match {
-			t.Errorf("unexpected stack: file on line %d:\n got: %q\nwant: %q", i+1, got[i].File(), w.File)
+// AssertTopFrame asserts that the innermost frame of the first stack trace in err's chain
+// matches the expected frame. Function and file of the expected frame are regular expressions.
+func AssertTopFrame(t TestingT, err error, want Frame) {
+	t.Helper()
+
+	tracer, ok := errors.As[errors.StackTracer](err)
+	if !ok || len(tracer.StackTrace()) == 0 {
+		t.Errorf("want error %q to have a stack trace", err)
+		return
+	}
+
+	diff, e := frameDiff(1, want, tracer.StackTrace()[0])
+	if e != nil {
+		t.Errorf("invalid expected frame: %v", e)
+		return
+	}
+	for _, d := range diff {
+		t.Errorf("unexpected top frame: %s", d)
+	}
+}
+
+// frameDiff describes every mismatch between the expected frame and the actual one
+// at the given position of a stack. Function and file of the expected frame are regular expressions.
+func frameDiff(position int, want Frame, got errors.Frame) ([]string, error) {
+	var diff []string
+
+	match, err := regexp.MatchString(want.Function, got.Name())
+	if err != nil {
+		return nil, err
+	}
+	if !match {
+		diff = append(diff, fmt.Sprintf("function on line %d:\n got: %q\nwant: %q", position, got.Name(), want.Function))
+	}
+
+	match, err = regexp.MatchString(want.File, got.File())
+	if err != nil {
+		return nil, err
+	}
+	if !match {
+		diff = append(diff, fmt.Sprintf("file on line %d:\n got: %q\nwant: %q", position, got.File(), want.File))
+	}
+
+	if want.Line != got.Line() {
+		diff = append(diff, fmt.Sprintf("line number on line %d:\n got: %d\nwant: %d", position, got.Line(), want.Line))
+	}
+
+	return diff, nil
+}
+
+// TestingT is a subset of testing.TB used by chain assertions.
+type TestingT interface {
+	Helper()
+	Errorf(format string, args ...interface{})
+}
+
+// AssertIs asserts that err's chain matches target as it is reported by errors.Is.
+func AssertIs(t TestingT, err, target error) {
+	t.Helper()
+
+	if !errors.Is(err, target) {
+		t.Errorf(`want error "%v" to match target "%v"`, err, target)
+	}
+}
+
+// AssertAs asserts that err's chain has an error of type T as it is reported by errors.As
+// and returns the found value.
+func AssertAs[T any](t TestingT, err error) T {
+	t.Helper()
+
+	value, ok := errors.As[T](err)
+	if !ok {
+		t.Errorf(`want error "%v" to have an error of type %s in chain`, err, reflect.TypeOf((*T)(nil)).Elem())
+	}
+
+	return value
+}
+
+// AssertSingleStack asserts that exactly one error in err's chain has a stack trace.
+// It guards against accidental double wrapping. The chain is walked by repeatedly calling Unwrap,
+// so branches of joined errors are not inspected.
+func AssertSingleStack(t TestingT, err error) {
+	t.Helper()
+
+	if count := countStacks(err); count != 1 {
+		t.Errorf(`want error "%v" to have exactly one stack in chain, got %d`, err, count)
+	}
+}
+
+// AssertNoStack asserts that no error in err's chain has a stack trace,
+// as it is expected for sentinel errors. The chain is walked by repeatedly calling Unwrap.
+func AssertNoStack(t TestingT, err error) {
+	t.Helper()
+
+	if count := countStacks(err); count != 0 {
+		t.Errorf(`want error "%v" to have no stack in chain, got %d`, err, count)
+	}
+}
+
+func countStacks(err error) int {
+	count := 0
+	for e, depth := err, 0; e != nil && depth < errors.MaxUnwrapDepth; e, depth = errors.Unwrap(e), depth+1 {
+		if _, ok := e.(errors.StackTracer); ok {
+			count++
 		}
+	}
+	return count
+}
+
+// AssertEqualError asserts that errors have equal messages and equal fields merged by errors.FieldsMap.
+// Stack traces are ignored, so the assertion is stable across changes of line numbers.
+func AssertEqualError(t TestingT, got, want error) {
+	t.Helper()
 
-		if w.Line != got[i].Line() {
-			t.Errorf("unexpected stack: line number on line %d:\n got: %d\nwant: %d", i+1, got[i].Line(), w.Line)
+	if got == nil || want == nil {
+		if got != want {
+			t.Errorf(`want error "%v", got "%v"`, want, got)
 		}
+		return
+	}
+	if got.Error() != want.Error() {
+		t.Errorf(`want error message "%s", got "%s"`, want.Error(), got.Error())
+	}
+	gotFields := errors.FieldsMap(got)
+	wantFields := errors.FieldsMap(want)
+	if !reflect.DeepEqual(gotFields, wantFields) {
+		t.Errorf(`want error fields %v, got %v`, wantFields, gotFields)
 	}
 }
@@ -0,0 +1,168 @@
+package errorstest_test
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/muonsoft/errors"
+	"github.com/muonsoft/errors/errorstest"
+)
+
+func TestAssertTopFrame(t *testing.T) {
+	err := errors.Errorf("ooh")
+
+	errorstest.AssertTopFrame(t, err, errorstest.Frame{
+		Function: `^github\.com/muonsoft/errors/errorstest_test\.TestAssertTopFrame$`,
+		File:     `/errorstest/mock_test\.go$`,
+		Line:     14,
+	})
+}
+
+type fakeT struct {
+	messages []string
+}
+
+func (t *fakeT) Helper() {}
+
+func (t *fakeT) Errorf(format string, args ...interface{}) {
+	t.messages = append(t.messages, fmt.Sprintf(format, args...))
+}
+
+type notFoundError struct{}
+
+func (notFoundError) Error() string { return "not found" }
+
+func TestAssertIs(t *testing.T) {
+	target := errors.New("target")
+	err := errors.Errorf("wrapped: %w", target)
+
+	passed := &fakeT{}
+	errorstest.AssertIs(passed, err, target)
+	failed := &fakeT{}
+	errorstest.AssertIs(failed, err, errors.New("other"))
+
+	if len(passed.messages) > 0 {
+		t.Errorf("want assertion to pass, got %v", passed.messages)
+	}
+	if len(failed.messages) != 1 {
+		t.Errorf("want assertion to fail once, got %v", failed.messages)
+	}
+}
+
+func TestAssertAs(t *testing.T) {
+	err := errors.Errorf("wrapped: %w", notFoundError{})
+
+	passed := &fakeT{}
+	value := errorstest.AssertAs[notFoundError](passed, err)
+	failed := &fakeT{}
+	errorstest.AssertAs[*os.PathError](failed, err)
+
+	if len(passed.messages) > 0 {
+		t.Errorf("want assertion to pass, got %v", passed.messages)
+	}
+	if value != (notFoundError{}) {
+		t.Errorf("want found value to be returned, got %#v", value)
+	}
+	if len(failed.messages) != 1 {
+		t.Errorf("want assertion to fail once, got %v", failed.messages)
+	} else if !strings.Contains(failed.messages[0], "*fs.PathError") {
+		t.Errorf("want failure message to contain the type, got %q", failed.messages[0])
+	}
+}
+
+func TestAssertSingleStack(t *testing.T) {
+	passed := &fakeT{}
+	errorstest.AssertSingleStack(passed, errors.Wrap(errors.Wrap(errors.New("ooh"))))
+	failed := &fakeT{}
+	errorstest.AssertSingleStack(failed, errors.New("ooh"))
+
+	if len(passed.messages) > 0 {
+		t.Errorf("want assertion to pass, got %v", passed.messages)
+	}
+	if len(failed.messages) != 1 {
+		t.Errorf("want assertion to fail once, got %v", failed.messages)
+	}
+}
+
+func TestAssertNoStack(t *testing.T) {
+	passed := &fakeT{}
+	errorstest.AssertNoStack(passed, errors.New("ooh"))
+	failed := &fakeT{}
+	errorstest.AssertNoStack(failed, errors.Wrap(errors.New("ooh")))
+
+	if len(passed.messages) > 0 {
+		t.Errorf("want assertion to pass, got %v", passed.messages)
+	}
+	if len(failed.messages) != 1 {
+		t.Errorf("want assertion to fail once, got %v", failed.messages)
+	}
+}
+
+func TestAssertEqualError(t *testing.T) {
+	want := errors.Errorf("ooh", errors.String("key", "value"), errors.Int("id", 1))
+	tests := []struct {
+		name       string
+		got        error
+		wantFailed bool
+	}{
+		{
+			name: "equal fields in other order",
+			got:  errors.Wrap(errors.Errorf("ooh", errors.Int("id", 1)), errors.String("key", "value")),
+		},
+		{
+			name:       "different field value",
+			got:        errors.Errorf("ooh", errors.String("key", "other"), errors.Int("id", 1)),
+			wantFailed: true,
+		},
+		{
+			name:       "missing field",
+			got:        errors.Errorf("ooh", errors.String("key", "value")),
+			wantFailed: true,
+		},
+		{
+			name:       "different message",
+			got:        errors.Errorf("other", errors.String("key", "value"), errors.Int("id", 1)),
+			wantFailed: true,
+		},
+		{
+			name:       "nil error",
+			got:        nil,
+			wantFailed: true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			fake := &fakeT{}
+
+			errorstest.AssertEqualError(fake, test.got, want)
+
+			if failed := len(fake.messages) > 0; failed != test.wantFailed {
+				t.Errorf("want assertion failure to be %t, got messages %v", test.wantFailed, fake.messages)
+			}
+		})
+	}
+}
+
+func TestAssertTopFrame_testingT(t *testing.T) {
+	err := errors.Errorf("ooh")
+	line := err.(errors.StackTracer).StackTrace()[0].Line()
+
+	passed := &fakeT{}
+	errorstest.AssertTopFrame(passed, err, errorstest.Frame{Line: line})
+	failed := &fakeT{}
+	errorstest.AssertTopFrame(failed, err, errorstest.Frame{Line: line + 1})
+	invalid := &fakeT{}
+	errorstest.AssertTopFrame(invalid, err, errorstest.Frame{Function: "(", Line: line})
+
+	if len(passed.messages) > 0 {
+		t.Errorf("want assertion to pass, got %v", passed.messages)
+	}
+	if len(failed.messages) != 1 {
+		t.Errorf("want assertion to fail once, got %v", failed.messages)
+	}
+	if len(invalid.messages) != 1 {
+		t.Errorf("want invalid frame to be reported once, got %v", invalid.messages)
+	}
+}
@@ -103,6 +103,24 @@ func TestFormat_Errorf(t *testing.T) {
 			"%+v",
 			"error\nkey: 123\n",
 		},
+		{
+			"%+v for error with int32 field",
+			errors.Errorf("%s", "error", errors.Int32("key", -1<<31)),
+			"%+v",
+			"error\nkey: -2147483648\n",
+		},
+		{
+			"%+v for error with int64 field",
+			errors.Errorf("%s", "error", errors.Int64("key", 1<<63-1)),
+			"%+v",
+			"error\nkey: 9223372036854775807\n",
+		},
+		{
+			"%+v for error with uint64 field",
+			errors.Errorf("%s", "error", errors.Uint64("key", 1<<64-1)),
+			"%+v",
+			"error\nkey: 18446744073709551615\n",
+		},
 		{
 			"%+v for error with float field",
 			errors.Errorf("%s", "error", errors.Float("key", 123.123)),
@@ -131,7 +149,7 @@ func TestFormat_Errorf(t *testing.T) {
 			"%+v for error with time field",
 			errors.Errorf("%s", "error", errors.Time("key", time.Date(2022, time.June, 13, 12, 0, 0, 0, time.UTC))),
 			"%+v",
-			"error\nkey: 2022\\-06\\-13 12:00:00 \\+0000 UTC\n",
+			"error\nkey: 2022\\-06\\-13T12:00:00Z\n",
 		},
 		{
 			"%+v for error with duration field",
@@ -139,12 +157,62 @@ func TestFormat_Errorf(t *testing.T) {
 			"%+v",
 			"error\nkey: 1h3m0s\n",
 		},
+		{
+			"%+v for error with durations field",
+			errors.Errorf("%s", "error", errors.Durations("key", []time.Duration{time.Second, time.Minute})),
+			"%+v",
+			"error\nkey: 1s, 1m0s\n",
+		},
 		{
 			"%+v for error with JSON field",
 			errors.Errorf("%s", "error", errors.JSON("key", []byte(`{"key":"value"}`))),
 			"%+v",
 			"error\nkey: {\\\"key\\\":\\\"value\\\"}\n",
 		},
+		{
+			"%+v for error with error field",
+			errors.Errorf("%s", "error", errors.Error("key", errors.New("cause"))),
+			"%+v",
+			"error\nkey: cause\n",
+		},
+		{
+			"%+v for error with errors field",
+			errors.Errorf("%s", "error", errors.Errors("key", []error{errors.New("foo"), nil})),
+			"%+v",
+			"error\nkey: foo, <nil>\n",
+		},
+		{
+			"%+v for error with bools field",
+			errors.Errorf("%s", "error", errors.Bools("key", []bool{true, false})),
+			"%+v",
+			"error\nkey: true, false\n",
+		},
+		{
+			"%+v for error with ints field",
+			errors.Errorf("%s", "error", errors.Ints("key", []int{1, -2, 3})),
+			"%+v",
+			"error\nkey: 1, -2, 3\n",
+		},
+		{
+			"%+v for error with floats field",
+			errors.Errorf("%s", "error", errors.Floats("key", []float64{0.5, 123.123})),
+			"%+v",
+			"error\nkey: 0.5, 123.123\n",
+		},
+		{
+			"%+v for error with fields added at once",
+			errors.Errorf(
+				"%s", "error",
+				errors.String("first", "1"),
+				errors.WithFields(
+					errors.StringField{Key: "second", Value: "2"},
+					errors.IntField{Key: "third", Value: 3},
+				),
+				errors.Bool("fourth", true),
+			),
+			"%+v",
+			"error\nfirst: 1\nsecond: 2\nthird: 3\nfourth: true\n",
+		},
 	}
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
@@ -152,3 +220,102 @@ func TestFormat_Errorf(t *testing.T) {
 		})
 	}
 }
+
+func TestFormat_joinedErrors(t *testing.T) {
+	err := errors.Wrap(
+		errors.Join(
+			errors.Wrap(
+				errors.Errorf("error 1", errors.String("key1", "value1")),
+				errors.String("key2", "value2"),
+			),
+			errors.Errorf("error 2", errors.String("key3", "value3")),
+			errors.New("error 3"),
+		),
+		errors.String("key", "value"),
+	)
+
+	assertFormatRegexp(t, err, "%v", "error 1\nerror 2\nerror 3$")
+	assertFormatRegexp(t, err, "%+v", "error 1\nerror 2\nerror 3\n"+
+		"key: value\n"+
+		"github.com/muonsoft/errors_test.TestFormat_joinedErrors\n"+
+		"\t.+/errors/format_test.go:226\n"+
+		".+\n"+
+		"\t.+\n"+
+		".+\n"+
+		"\t.+\n"+
+		"---\n"+
+		"error 1\n"+
+		"key2: value2\n"+
+		"key1: value1\n"+
+		"github.com/muonsoft/errors_test.TestFormat_joinedErrors\n"+
+		"\t.+/errors/format_test.go:228\n"+
+		".+\n"+
+		"\t.+\n"+
+		".+\n"+
+		"\t.+\n"+
+		"---\n"+
+		"error 2\n"+
+		"key3: value3\n"+
+		"github.com/muonsoft/errors_test.TestFormat_joinedErrors\n"+
+		"\t.+/errors/format_test.go:231\n"+
+		".+\n"+
+		"\t.+\n"+
+		".+\n"+
+		"\t.+\n"+
+		"---\n"+
+		"error 3$",
+	)
+}
+
+func TestFormat_stackTraceOneLine(t *testing.T) {
+	trace := stackTraceOf(t, errors.Errorf("ooh"))[:2]
+	want := "^github.com/muonsoft/errors_test.TestFormat_stackTraceOneLine .+/errors/format_test.go:\\d+; " +
+		"testing.tRunner .+/testing/testing.go:\\d+$"
+
+	assertFormatRegexp(t, trace, "% v", want)
+	assertFormatRegexp(t, trace, "%v", `^\[format_test.go:\d+ testing.go:\d+\]$`)
+	assertFormatRegexp(t, trace.OneLine(), "%s", want)
+}
+
+func TestFormat_floats(t *testing.T) {
+	tests := []struct {
+		name  string
+		value float64
+		want  string
+	}{
+		{name: "decimal", value: 123.123, want: "123.123"},
+		{name: "integer", value: 2, want: "2"},
+		{name: "very large", value: 1e21, want: `1e\+21`},
+		{name: "very small", value: 0.0000001, want: "1e-07"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := errors.Errorf("error", errors.Float("key", test.value))
+
+			assertFormatRegexp(t, err, "%+v", "error\nkey: "+test.want+"$\n")
+		})
+	}
+}
+
+func TestFormat_joinError(t *testing.T) {
+	joined := errors.Unwrap(errors.Join(
+		errors.Errorf("error 1", errors.String("key1", "value1")),
+		errors.Errorf("error 2", errors.String("key2", "value2")),
+	))
+
+	assertFormatRegexp(t, joined, "%s", "^error 1\nerror 2$")
+	assertFormatRegexp(t, joined, "%v", "^error 1\nerror 2$")
+	assertFormatRegexp(t, joined, "%+v", "^error 1\n"+
+		"key1: value1\n"+
+		"github.com/muonsoft/errors_test.TestFormat_joinError\n"+
+		"\t.+/errors/format_test.go:\\d+\n"+
+		".+\n"+
+		"\t.+\n"+
+		".+\n"+
+		"\t.+\n"+
+		"---\n"+
+		"error 2\n"+
+		"key2: value2\n"+
+		"github.com/muonsoft/errors_test.TestFormat_joinError\n"+
+		"\t.+/errors/format_test.go:\\d+\n")
+}
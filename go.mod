@@ -2,6 +2,16 @@ module github.com/muonsoft/errors
 
 go 1.20
 
-require github.com/sirupsen/logrus v1.8.1
+require (
+	github.com/sirupsen/logrus v1.8.1
+	go.opentelemetry.io/otel/trace v1.19.0
+	google.golang.org/grpc v1.57.1
+)
 
-require golang.org/x/sys v0.0.0-20191026070338-33540a1f6037 // indirect
+require (
+	github.com/golang/protobuf v1.5.3 // indirect
+	go.opentelemetry.io/otel v1.19.0 // indirect
+	golang.org/x/sys v0.7.0 // indirect
+	google.golang.org/genproto/googleapis/rpc v0.0.0-20230525234030-28d5490b6b19 // indirect
+	google.golang.org/protobuf v1.30.0 // indirect
+)
@@ -1,5 +1,12 @@
 package errors
 
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+)
+
 // Join returns an error that wraps the given errors with a stack trace
 // at the point Join is called. Any nil error values are discarded.
 // Join returns nil if errs contains no non-nil values.
@@ -9,6 +16,52 @@ package errors
 // If there is only one error in chain, then it's stack trace will be
 // preserved if present.
 func Join(errs ...error) error {
+	return join(&joinError{separator: "\n"}, errs)
+}
+
+// JoinWith works like Join, but the error formats as the concatenation of the strings
+// obtained by calling the Error method of each element of errs, with the separator
+// between each string. For example, it can be used with "; " separator for single-line logs.
+func JoinWith(separator string, errs ...error) error {
+	return join(&joinError{separator: separator}, errs)
+}
+
+// MergeStrategy defines how fields with the same key set in different branches
+// of errors joined by JoinInto are merged.
+type MergeStrategy int
+
+const (
+	// LastBranchWins keeps only fields of the last branch that sets a key.
+	LastBranchWins MergeStrategy = iota + 1
+	// NumberedSuffix keeps fields of all branches: the key of the first branch
+	// is not changed and keys of the next branches get the suffix with the branch
+	// number of the key, for example "id", "id_2", "id_3".
+	NumberedSuffix
+)
+
+// JoinInto works like Join, but fields of joined errors are merged into one set
+// by the strategy, when the error is logged. Without merging, a logger receives
+// fields with the same key from different branches and keeps one of them silently.
+// Merging is applied to fields passed to loggers by Log function. JSON keeps fields
+// of every joined error in its own object, so it is not affected.
+// The strategy can be read by GetMergeStrategy function.
+func JoinInto(strategy MergeStrategy, errs ...error) error {
+	return join(&joinError{separator: "\n", mergeStrategy: strategy}, errs)
+}
+
+// GetMergeStrategy returns the strategy of merging fields of errors joined by JoinInto.
+func GetMergeStrategy(err error) (MergeStrategy, bool) {
+	joined, ok := As[*joinError](err)
+	if !ok || joined.mergeStrategy == 0 {
+		return 0, false
+	}
+
+	return joined.mergeStrategy, true
+}
+
+// join collects non-nil errors into the joined error e. It must be called directly
+// by an exported function, because it skips one caller in a stack trace.
+func join(e *joinError, errs []error) error {
 	n := 0
 	for _, err := range errs {
 		if err != nil {
@@ -21,19 +74,19 @@ func Join(errs ...error) error {
 	if n == 1 {
 		for _, err := range errs {
 			if err != nil {
-				if isWrapper(err) {
+				if hasStack(err) {
 					return err
 				}
 
 				return &stacked{
-					wrapped: &wrapped{wrapped: err},
-					stack:   newStack(0),
+					wrapped: wrapped{wrapped: err},
+					stack:   stack{pcs: callers(1, 0)},
 				}
 			}
 		}
 	}
 
-	e := &joinError{errs: make([]error, 0, n)}
+	e.errs = make([]error, 0, n)
 
 	for _, err := range errs {
 		if err != nil {
@@ -42,17 +95,30 @@ func Join(errs ...error) error {
 	}
 
 	return &stacked{
-		wrapped: &wrapped{wrapped: e},
-		stack:   newStack(0),
+		wrapped: wrapped{wrapped: e},
+		stack:   stack{pcs: callers(1, 0)},
 	}
 }
 
 type joinError struct {
-	errs []error
+	errs          []error
+	separator     string
+	mergeStrategy MergeStrategy
 }
 
 func (e *joinError) LogFields(logger FieldLogger) {
-	logFieldsFromErrors(logger, e.errs)
+	if e.mergeStrategy == 0 {
+		logFieldsFromErrors(logger, e.errs)
+		return
+	}
+
+	branches := make([][]Field, len(e.errs))
+	for i, err := range e.errs {
+		collector := &fieldCollector{}
+		logFieldsFromErrors(collector, []error{err})
+		branches[i] = collector.fields
+	}
+	mergeFields(logger, branches, e.mergeStrategy)
 }
 
 func (e *joinError) Error() string {
@@ -60,7 +126,7 @@ func (e *joinError) Error() string {
 
 	for i, err := range e.errs {
 		if i > 0 {
-			b = append(b, '\n')
+			b = append(b, e.separator...)
 		}
 		b = append(b, err.Error()...)
 	}
@@ -72,11 +138,49 @@ func (e *joinError) Unwrap() []error {
 	return e.errs
 }
 
+// Format formats joined errors according to the fmt.Formatter interface.
+//
+//	%s, %v  messages of joined errors separated by the separator (a newline for Join)
+//	%q      quoted messages of joined errors
+//	%+v     every joined error formatted by %+v (with fields and a stack trace)
+//	        separated by the rule line "---"
+func (e *joinError) Format(s fmt.State, verb rune) {
+	switch verb {
+	case 'v':
+		if s.Flag('+') {
+			for i, err := range e.errs {
+				if i > 0 {
+					io.WriteString(s, "\n"+joinedErrorsRule+"\n")
+				}
+				fmt.Fprintf(s, "%+v", err)
+			}
+			return
+		}
+		fallthrough
+	case 's':
+		io.WriteString(s, e.Error())
+	case 'q':
+		fmt.Fprintf(s, "%q", e.Error())
+	}
+}
+
+const joinedErrorsRule = "---"
+
+// MarshalJSON returns the JSON representation of joined errors: the joined message
+// and the "errors" array with every error marshaled with its own fields and stack trace.
+func (e *joinError) MarshalJSON() ([]byte, error) {
+	return json.Marshal(errorData(e, defaultMarshalConfig))
+}
+
 func logFieldsFromErrors(logger FieldLogger, errs []error) {
+	logFieldsFromErrorsAt(logger, errs, 0, &unwrapWalk{})
+}
+
+func logFieldsFromErrorsAt(logger FieldLogger, errs []error, depth int, walk *unwrapWalk) {
 	for _, err := range errs {
-		for w := err; w != nil; w = Unwrap(w) {
-			if j, ok := w.(interface{ Unwrap() []error }); ok {
-				logFieldsFromErrors(logger, j.Unwrap())
+		for w, d := err, depth; w != nil && walk.next(d); w, d = Unwrap(w), d+1 {
+			if j, ok := w.(interface{ Unwrap() []error }); ok && walk.enter(w) {
+				logFieldsFromErrorsAt(logger, j.Unwrap(), d+1, walk)
 			}
 			if loggable, ok := w.(LoggableError); ok {
 				loggable.LogFields(logger)
@@ -84,3 +188,96 @@ func logFieldsFromErrors(logger FieldLogger, errs []error) {
 		}
 	}
 }
+
+func mergeFields(logger FieldLogger, branches [][]Field, strategy MergeStrategy) {
+	switch strategy {
+	case LastBranchWins:
+		lastBranches := make(map[string]int)
+		for i, fields := range branches {
+			for _, field := range fields {
+				lastBranches[fieldKey(field)] = i
+			}
+		}
+		for i, fields := range branches {
+			for _, field := range fields {
+				if lastBranches[fieldKey(field)] == i {
+					field.Set(logger)
+				}
+			}
+		}
+	case NumberedSuffix:
+		counts := make(map[string]int)
+		for _, fields := range branches {
+			numbers := make(map[string]int)
+			for _, field := range fields {
+				key := fieldKey(field)
+				if _, ok := numbers[key]; !ok {
+					counts[key]++
+					numbers[key] = counts[key]
+				}
+				if numbers[key] == 1 {
+					field.Set(logger)
+				} else {
+					field.Set(keyLogger{suffix: "_" + strconv.Itoa(numbers[key]), logger: logger})
+				}
+			}
+		}
+	}
+}
+
+func fieldKey(field Field) string {
+	var key string
+	field.Set(keyReader{keyValueLogger(func(k string, value interface{}) {
+		key = k
+	})})
+	return key
+}
+
+// keyReader reads the key of a field. A group is read as a whole, so its own key is returned.
+type keyReader struct {
+	keyValueLogger
+}
+
+func (r keyReader) setGroup(key string, fields []Field) {
+	r.keyValueLogger(key, nil)
+}
+
+// formatJoinedErrors writes verbose representation of every error joined in err's chain:
+// the message, fields and stack trace of each error preceded by the rule line, as by joinError.Format.
+func formatJoinedErrors(w io.Writer, err error) {
+	walk := &unwrapWalk{}
+	for e, depth := err, 0; e != nil && walk.next(depth); e, depth = Unwrap(e), depth+1 {
+		if joined, ok := e.(interface{ Unwrap() []error }); ok {
+			if walk.enter(e) {
+				formatErrors(w, joined.Unwrap(), depth+1, walk)
+			}
+			return
+		}
+	}
+}
+
+func formatErrors(w io.Writer, errs []error, depth int, walk *unwrapWalk) {
+	for _, err := range errs {
+		if !walk.next(depth) {
+			return
+		}
+		if joined, ok := err.(interface{ Unwrap() []error }); ok && !isFormattedJoin(err) {
+			if walk.enter(err) {
+				formatErrors(w, joined.Unwrap(), depth+1, walk)
+			}
+			continue
+		}
+		fmt.Fprintf(w, "\n"+joinedErrorsRule+"\n%+v", err)
+	}
+}
+
+// isFormattedJoin reports whether the joined error has its own verbose format,
+// that should be used instead of formatting its errors one by one.
+// Errors joined by this package are flattened, so the output is consistent.
+func isFormattedJoin(err error) bool {
+	if _, ok := err.(*joinError); ok {
+		return false
+	}
+	_, ok := err.(fmt.Formatter)
+	return ok
+}
@@ -2,9 +2,11 @@ package errors_test
 
 import (
 	"fmt"
+	"reflect"
 	"testing"
 
 	"github.com/muonsoft/errors"
+	"github.com/muonsoft/errors/errorstest"
 )
 
 func TestJoin_ReturnsNil(t *testing.T) {
@@ -80,3 +82,152 @@ func TestJoin_ErrorMethod(t *testing.T) {
 		})
 	}
 }
+
+func TestJoinWith(t *testing.T) {
+	err1 := errors.New("err1")
+	err2 := errors.New("err2")
+	tests := []struct {
+		errs []error
+		want string
+	}{
+		{
+			errs: []error{err1},
+			want: "err1",
+		},
+		{
+			errs: []error{err1, err2},
+			want: "err1; err2",
+		},
+		{
+			errs: []error{nil, err1, nil, err2, nil},
+			want: "err1; err2",
+		},
+	}
+	for _, test := range tests {
+		t.Run(fmt.Sprintf("%v", test.errs), func(t *testing.T) {
+			err := errors.JoinWith("; ", test.errs...)
+
+			if got := err.Error(); got != test.want {
+				t.Errorf("JoinWith().Error() = %q; want %q", got, test.want)
+			}
+			for _, e := range test.errs {
+				if e != nil && !errors.Is(err, e) {
+					t.Errorf("want joined error to match %v", e)
+				}
+			}
+			assertSingleStack(t, err)
+		})
+	}
+}
+
+func TestJoinWith_ReturnsNil(t *testing.T) {
+	if err := errors.JoinWith("; "); err != nil {
+		t.Errorf(`errors.JoinWith("; ") = %v, want nil`, err)
+	}
+	if err := errors.JoinWith("; ", nil, nil); err != nil {
+		t.Errorf(`errors.JoinWith("; ", nil, nil) = %v, want nil`, err)
+	}
+}
+
+func TestJoinWith_preservesStackOfSingleError(t *testing.T) {
+	err := errors.Errorf("ooh")
+
+	if joined := errors.JoinWith("; ", nil, err); joined != err {
+		t.Errorf("want single error with stack to be returned as is, got %#v", joined)
+	}
+	stacked := stackTraceOf(t, errors.JoinWith("; ", errors.New("ooh")))
+	if name := stacked[0].Name(); name != "github.com/muonsoft/errors_test.TestJoinWith_preservesStackOfSingleError" {
+		t.Errorf("want stack trace to start at the caller, got %s", name)
+	}
+}
+
+func TestJoinInto(t *testing.T) {
+	first := errors.Errorf("first", errors.String("key", "first"), errors.Int("id", 1))
+	second := errors.Wrap(errors.Errorf("second", errors.String("key", "second")), errors.Int("id", 2))
+	tests := []struct {
+		strategy errors.MergeStrategy
+		want     map[string]interface{}
+	}{
+		{
+			strategy: errors.LastBranchWins,
+			want:     map[string]interface{}{"key": "second", "id": 2},
+		},
+		{
+			strategy: errors.NumberedSuffix,
+			want:     map[string]interface{}{"key": "first", "id": 1, "key_2": "second", "id_2": 2},
+		},
+	}
+	for _, test := range tests {
+		t.Run(fmt.Sprintf("strategy %d", test.strategy), func(t *testing.T) {
+			err := errors.JoinInto(test.strategy, first, second)
+
+			logger := errorstest.NewLogger()
+			errors.Log(err, logger)
+
+			if len(logger.Fields) != len(test.want) {
+				t.Errorf("want fields %v, got %v", test.want, logger.Fields)
+			}
+			for key, value := range test.want {
+				logger.AssertField(t, key, value)
+			}
+			if strategy, ok := errors.GetMergeStrategy(err); !ok || strategy != test.strategy {
+				t.Errorf("want merge strategy %d, got %d", test.strategy, strategy)
+			}
+		})
+	}
+}
+
+func TestGetMergeStrategy_join(t *testing.T) {
+	if _, ok := errors.GetMergeStrategy(errors.Join(errors.New("first"), errors.New("second"))); ok {
+		t.Error("want no merge strategy for errors.Join")
+	}
+}
+
+func TestJoinInto_groups(t *testing.T) {
+	first := errors.Errorf("first", errors.Group("ctx", errors.IntField{Key: "id", Value: 1}))
+	second := errors.Errorf("second", errors.Group("ctx", errors.IntField{Key: "x", Value: 2}))
+	tests := []struct {
+		strategy errors.MergeStrategy
+		want     map[string]interface{}
+	}{
+		{
+			strategy: errors.LastBranchWins,
+			want:     map[string]interface{}{"ctx.x": 2},
+		},
+		{
+			strategy: errors.NumberedSuffix,
+			want:     map[string]interface{}{"ctx.id": 1, "ctx_2.x": 2},
+		},
+	}
+	for _, test := range tests {
+		t.Run(fmt.Sprintf("strategy %d", test.strategy), func(t *testing.T) {
+			logger := errorstest.NewLogger()
+			errors.Log(errors.JoinInto(test.strategy, first, second), logger)
+
+			if len(logger.Fields) != len(test.want) {
+				t.Errorf("want fields %v, got %v", test.want, logger.Fields)
+			}
+			for key, value := range test.want {
+				logger.AssertField(t, key, value)
+			}
+		})
+	}
+}
+
+func TestJoinInto_fieldAccessors(t *testing.T) {
+	err := errors.JoinInto(
+		errors.NumberedSuffix,
+		errors.Errorf("first", errors.Int("id", 1)),
+		errors.Errorf("second", errors.Int("id", 2)),
+	)
+
+	if want, got := map[string]interface{}{"id": 1, "id_2": 2}, errors.FieldsMap(err); !reflect.DeepEqual(got, want) {
+		t.Errorf("want fields map %v, got %v", want, got)
+	}
+	if want, got := map[string]string{"id": "1", "id_2": "2"}, errors.FieldsStringMap(err); !reflect.DeepEqual(got, want) {
+		t.Errorf("want fields string map %v, got %v", want, got)
+	}
+	if got := errors.DuplicateKeys(err); got != nil {
+		t.Errorf("want no duplicate keys, got %v", got)
+	}
+}
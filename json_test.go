@@ -87,15 +87,7 @@ func TestJoinedError_MarshalJSON(t *testing.T) {
 	if e != nil {
 		t.Fatalf("expected %#v to be marshalable into json: %v", err, e)
 	}
-	var jsonError struct {
-		Error      string                `json:"error"`
-		StackTrace errorstest.StackTrace `json:"stackTrace"`
-		Key1       string                `json:"key1"`
-		Key2       string                `json:"key2"`
-		Key3       string                `json:"key3"`
-		Key4       string                `json:"key4"`
-		Key5       string                `json:"key5"`
-	}
+	var jsonError JoinedJSONError
 	e = json.Unmarshal(jsonData, &jsonError)
 	if e != nil {
 		t.Fatalf("failed to unmarshal json: %v", e)
@@ -111,26 +103,145 @@ func TestJoinedError_MarshalJSON(t *testing.T) {
 			Line:     74,
 		},
 	})
-	if jsonError.Key1 != "value1" {
-		t.Errorf(`expected %#v to have key "key1"`, err)
+	if len(jsonError.Errors) != 3 {
+		t.Fatalf(`expected %#v to have 3 nested errors, got %d`, err, len(jsonError.Errors))
 	}
-	if jsonError.Key2 != "value2" {
-		t.Errorf(`expected %#v to have key "key2"`, err)
+	if nested := jsonError.Errors[0]; nested.Error != "error 1" || nested.Key1 != "value1" || nested.Key2 != "value2" {
+		t.Errorf(`expected first error to have keys "key1" and "key2", got %#v`, nested)
 	}
-	if jsonError.Key3 != "value3" {
-		t.Errorf(`expected %#v to have key "key3"`, err)
+	if nested := jsonError.Errors[1]; nested.Error != "error 2" || nested.Key3 != "value3" || len(nested.StackTrace) == 0 {
+		t.Errorf(`expected second error to have key "key3" and stack trace, got %#v`, nested)
 	}
-	if jsonError.Key4 != "value4" {
-		t.Errorf(`expected %#v to have key "key4"`, err)
-	}
-	if jsonError.Key5 != "value5" {
-		t.Errorf(`expected %#v to have key "key5"`, err)
+	if nested := jsonError.Errors[2]; nested.Error != "error 3\nerror 4" || len(nested.Errors) != 2 ||
+		nested.Errors[0].Key4 != "value4" || nested.Errors[1].Key5 != "value5" {
+		t.Errorf(`expected third error to have nested errors with keys "key4" and "key5", got %#v`, nested)
 	}
 }
 
+type JoinedJSONError struct {
+	Error      string                `json:"error"`
+	StackTrace errorstest.StackTrace `json:"stackTrace"`
+	Key1       string                `json:"key1"`
+	Key2       string                `json:"key2"`
+	Key3       string                `json:"key3"`
+	Key4       string                `json:"key4"`
+	Key5       string                `json:"key5"`
+	Errors     []JoinedJSONError     `json:"errors"`
+}
+
 type JSONError struct {
 	Error      string                `json:"error"`
 	StackTrace errorstest.StackTrace `json:"stackTrace"`
 	Key        string                `json:"key"`
 	DeepKey    string                `json:"deepKey"`
 }
+
+func TestErrorField_MarshalJSON(t *testing.T) {
+	err := errors.Errorf(
+		"ooh",
+		errors.Error("plain", errors.New("plain error")),
+		errors.Error("loggable", errors.Errorf("loggable error", errors.String("key", "value"))),
+		errors.Errors("list", []error{errors.New("list error"), nil}),
+	)
+	jsonData, e := json.Marshal(err)
+	if e != nil {
+		t.Fatalf("expected %#v to be marshalable into json: %v", err, e)
+	}
+	var jsonError struct {
+		Plain    string `json:"plain"`
+		Loggable struct {
+			Error      string                `json:"error"`
+			StackTrace errorstest.StackTrace `json:"stackTrace"`
+			Key        string                `json:"key"`
+		} `json:"loggable"`
+		List []*string `json:"list"`
+	}
+	e = json.Unmarshal(jsonData, &jsonError)
+	if e != nil {
+		t.Fatalf("failed to unmarshal json: %v", e)
+	}
+
+	if jsonError.Plain != "plain error" {
+		t.Errorf(`want "plain" to be "plain error", got "%s"`, jsonError.Plain)
+	}
+	if jsonError.Loggable.Error != "loggable error" {
+		t.Errorf(`want "loggable.error" to be "loggable error", got "%s"`, jsonError.Loggable.Error)
+	}
+	if jsonError.Loggable.Key != "value" {
+		t.Errorf(`want "loggable.key" to be "value", got "%s"`, jsonError.Loggable.Key)
+	}
+	assertStackRegexp(t, jsonError.Loggable.StackTrace, errorstest.StackTrace{
+		{
+			Function: "github.com/muonsoft/errors_test.TestErrorField_MarshalJSON",
+			File:     ".+/errors/json_test.go",
+			Line:     143,
+		},
+	})
+	if len(jsonError.List) != 2 || jsonError.List[0] == nil || *jsonError.List[0] != "list error" || jsonError.List[1] != nil {
+		t.Errorf(`want "list" to be ["list error", null], got %s`, jsonData)
+	}
+}
+
+func TestIntegerFields_MarshalJSON(t *testing.T) {
+	err := errors.Errorf(
+		"ooh",
+		errors.Int64("int64", 1<<63-1),
+		errors.Int32("int32", -1<<31),
+		errors.Uint64("uint64", 1<<64-1),
+	)
+	jsonData, e := json.Marshal(err)
+	if e != nil {
+		t.Fatalf("expected %#v to be marshalable into json: %v", err, e)
+	}
+	var jsonError struct {
+		Int64  int64  `json:"int64"`
+		Int32  int32  `json:"int32"`
+		Uint64 uint64 `json:"uint64"`
+	}
+	e = json.Unmarshal(jsonData, &jsonError)
+	if e != nil {
+		t.Fatalf("failed to unmarshal json: %v", e)
+	}
+
+	if jsonError.Int64 != 1<<63-1 {
+		t.Errorf(`want "int64" to be %d, got %d`, int64(1<<63-1), jsonError.Int64)
+	}
+	if jsonError.Int32 != -1<<31 {
+		t.Errorf(`want "int32" to be %d, got %d`, -1<<31, jsonError.Int32)
+	}
+	if jsonError.Uint64 != 1<<64-1 {
+		t.Errorf(`want "uint64" to be %d, got %d`, uint64(1<<64-1), jsonError.Uint64)
+	}
+}
+
+func TestSliceFields_MarshalJSON(t *testing.T) {
+	err := errors.Errorf(
+		"ooh",
+		errors.Bools("bools", []bool{true, false}),
+		errors.Ints("ints", []int{1, -2, 3}),
+		errors.Floats("floats", []float64{0.5, 123.123}),
+	)
+	jsonData, e := json.Marshal(err)
+	if e != nil {
+		t.Fatalf("expected %#v to be marshalable into json: %v", err, e)
+	}
+	var jsonError struct {
+		Bools  json.RawMessage `json:"bools"`
+		Ints   json.RawMessage `json:"ints"`
+		Floats json.RawMessage `json:"floats"`
+	}
+	e = json.Unmarshal(jsonData, &jsonError)
+	if e != nil {
+		t.Fatalf("failed to unmarshal json: %v", e)
+	}
+
+	if string(jsonError.Bools) != `[true,false]` {
+		t.Errorf(`want "bools" to be [true,false], got %s`, jsonError.Bools)
+	}
+	if string(jsonError.Ints) != `[1,-2,3]` {
+		t.Errorf(`want "ints" to be [1,-2,3], got %s`, jsonError.Ints)
+	}
+	if string(jsonError.Floats) != `[0.5,123.123]` {
+		t.Errorf(`want "floats" to be [0.5,123.123], got %s`, jsonError.Floats)
+	}
+}
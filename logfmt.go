@@ -0,0 +1,76 @@
+package errors
+
+import (
+	"io"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// LogfmtWriter is a FieldLogger that writes fields into the writer in the logfmt format:
+// space-separated key=value pairs. Values are rendered the same way as in the "%+v" output
+// and are quoted if they are empty or contain spaces, quotes, equal signs or control characters.
+// The stack trace is written as the "stackTrace" key with frames separated by "; ".
+type LogfmtWriter struct {
+	textLogger
+	writer io.Writer
+	count  int
+}
+
+// NewLogfmtWriter creates a LogfmtWriter that writes fields into the writer.
+func NewLogfmtWriter(writer io.Writer) *LogfmtWriter {
+	w := &LogfmtWriter{writer: writer}
+	w.textLogger = w.writePair
+
+	return w
+}
+
+func (w *LogfmtWriter) SetStackTrace(trace StackTrace) {
+	w.writePair("stackTrace", trace.OneLine())
+}
+
+func (w *LogfmtWriter) writePair(key string, value string) {
+	if w.count > 0 {
+		io.WriteString(w.writer, " ")
+	}
+	w.count++
+	io.WriteString(w.writer, key+"="+quoteLogfmt(value))
+}
+
+// WriteLogfmt writes the message, the fields and the innermost stack trace of err's chain
+// into the writer as a single logfmt line, for example:
+//
+//	error="find product: not found" productID=123 stackTrace="main.main /app/main.go:12; ..."
+//
+// If err is nil, nothing is written.
+func WriteLogfmt(writer io.Writer, err error) {
+	if err == nil {
+		return
+	}
+
+	w := NewLogfmtWriter(writer)
+	w.SetString("error", err.Error())
+	logFields(err, w)
+	var trace StackTrace
+	for e, depth := err, 0; e != nil && depth < MaxUnwrapDepth; e, depth = Unwrap(e), depth+1 {
+		if tracer, ok := e.(StackTracer); ok {
+			trace = limitedStackTrace(tracer)
+		}
+	}
+	if trace != nil {
+		w.SetStackTrace(trace)
+	}
+	io.WriteString(writer, "\n")
+}
+
+func quoteLogfmt(value string) string {
+	if value == "" {
+		return `""`
+	}
+	if strings.IndexFunc(value, func(r rune) bool {
+		return r <= ' ' || r == '=' || r == '"' || r == unicode.ReplacementChar || unicode.IsControl(r)
+	}) >= 0 {
+		return strconv.Quote(value)
+	}
+	return value
+}
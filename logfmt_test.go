@@ -0,0 +1,40 @@
+package errors_test
+
+import (
+	"bytes"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/muonsoft/errors"
+)
+
+func TestWriteLogfmt(t *testing.T) {
+	err := errors.Errorf(
+		"not found",
+		errors.String("phrase", "a b"),
+		errors.String("quoted", `say "hi"`),
+		errors.String("empty", ""),
+		errors.Int("id", 123),
+		errors.Duration("elapsed", time.Second),
+	)
+	var buffer bytes.Buffer
+
+	errors.WriteLogfmt(&buffer, err)
+
+	want := `^error="not found" phrase="a b" quoted="say \\"hi\\"" empty="" id=123 elapsed=1s ` +
+		`stackTrace="github.com/muonsoft/errors_test.TestWriteLogfmt .+/errors/logfmt_test.go:13; .+"\n$`
+	if !regexp.MustCompile(want).MatchString(buffer.String()) {
+		t.Errorf("want logfmt output to match %q, got %q", want, buffer.String())
+	}
+}
+
+func TestWriteLogfmt_nil(t *testing.T) {
+	var buffer bytes.Buffer
+
+	errors.WriteLogfmt(&buffer, nil)
+
+	if buffer.Len() > 0 {
+		t.Errorf("want nothing to be written, got %q", buffer.String())
+	}
+}
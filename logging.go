@@ -1,6 +1,8 @@
 package errors
 
 import (
+	"encoding/base64"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"time"
@@ -9,15 +11,25 @@ import (
 // FieldLogger used to set error fields into structured logger.
 type FieldLogger interface {
 	SetBool(key string, value bool)
+	SetBools(key string, values []bool)
 	SetInt(key string, value int)
+	SetInts(key string, values []int)
+	SetInt32(key string, value int32)
+	SetInt64(key string, value int64)
 	SetUint(key string, value uint)
+	SetUint64(key string, value uint64)
 	SetFloat(key string, value float64)
+	SetFloats(key string, values []float64)
 	SetString(key string, value string)
 	SetStrings(key string, values []string)
 	SetValue(key string, value interface{})
 	SetTime(key string, value time.Time)
 	SetDuration(key string, value time.Duration)
+	SetDurations(key string, values []time.Duration)
 	SetJSON(key string, value json.RawMessage)
+	SetBytes(key string, value []byte, encoding BytesEncoding)
+	SetError(key string, value error)
+	SetErrors(key string, values []error)
 	SetStackTrace(trace StackTrace)
 }
 
@@ -39,9 +51,9 @@ func Log(err error, logger Logger) {
 		return
 	}
 
-	for e := err; e != nil; e = errors.Unwrap(e) {
-		if s, ok := e.(stackTracer); ok {
-			logger.SetStackTrace(s.StackTrace())
+	for e, depth := err, 0; e != nil && depth < MaxUnwrapDepth; e, depth = errors.Unwrap(e), depth+1 {
+		if s, ok := e.(StackTracer); ok {
+			logger.SetStackTrace(limitedStackTrace(s))
 		}
 	}
 	logFields(err, logger)
@@ -49,15 +61,35 @@ func Log(err error, logger Logger) {
 	logger.Log(err.Error())
 }
 
-func logFields(err error, logger Logger) {
-	for e := err; e != nil; e = errors.Unwrap(e) {
+// LogHere works like Log, but also captures a stack trace at the point LogHere is called
+// and passes it to the logger as the "logStackTrace" field. So the log contains both
+// the place where the error was created and the place where it was handled.
+func LogHere(err error, logger Logger) {
+	if err == nil {
+		return
+	}
+
+	logger.SetValue("logStackTrace", (&stack{pcs: callers(0, 0)}).StackTrace())
+	Log(err, logger)
+}
+
+func logFields(err error, logger FieldLogger) {
+	logFieldsAt(err, logger, 0, &unwrapWalk{})
+}
+
+func logFieldsAt(err error, logger FieldLogger, depth int, walk *unwrapWalk) {
+	for e := err; e != nil && walk.next(depth); e, depth = errors.Unwrap(e), depth+1 {
 		if w, ok := e.(LoggableError); ok {
 			w.LogFields(logger)
 		}
 
-		if joined, ok := e.(interface{ Unwrap() []error }); ok {
+		if _, ok := e.(*joinError); ok {
+			// fields of joined errors are logged by joinError itself
+			continue
+		}
+		if joined, ok := e.(interface{ Unwrap() []error }); ok && walk.enter(e) {
 			for _, u := range joined.Unwrap() {
-				logFields(u, logger)
+				logFieldsAt(u, logger, depth+1, walk)
 			}
 		}
 	}
@@ -72,6 +104,15 @@ func (f BoolField) Set(logger FieldLogger) {
 	logger.SetBool(f.Key, f.Value)
 }
 
+type BoolsField struct {
+	Key    string
+	Values []bool
+}
+
+func (f BoolsField) Set(logger FieldLogger) {
+	logger.SetBools(f.Key, f.Values)
+}
+
 type IntField struct {
 	Key   string
 	Value int
@@ -81,6 +122,33 @@ func (f IntField) Set(logger FieldLogger) {
 	logger.SetInt(f.Key, f.Value)
 }
 
+type IntsField struct {
+	Key    string
+	Values []int
+}
+
+func (f IntsField) Set(logger FieldLogger) {
+	logger.SetInts(f.Key, f.Values)
+}
+
+type Int32Field struct {
+	Key   string
+	Value int32
+}
+
+func (f Int32Field) Set(logger FieldLogger) {
+	logger.SetInt32(f.Key, f.Value)
+}
+
+type Int64Field struct {
+	Key   string
+	Value int64
+}
+
+func (f Int64Field) Set(logger FieldLogger) {
+	logger.SetInt64(f.Key, f.Value)
+}
+
 type UintField struct {
 	Key   string
 	Value uint
@@ -90,6 +158,15 @@ func (f UintField) Set(logger FieldLogger) {
 	logger.SetUint(f.Key, f.Value)
 }
 
+type Uint64Field struct {
+	Key   string
+	Value uint64
+}
+
+func (f Uint64Field) Set(logger FieldLogger) {
+	logger.SetUint64(f.Key, f.Value)
+}
+
 type FloatField struct {
 	Key   string
 	Value float64
@@ -99,6 +176,15 @@ func (f FloatField) Set(logger FieldLogger) {
 	logger.SetFloat(f.Key, f.Value)
 }
 
+type FloatsField struct {
+	Key    string
+	Values []float64
+}
+
+func (f FloatsField) Set(logger FieldLogger) {
+	logger.SetFloats(f.Key, f.Values)
+}
+
 type StringField struct {
 	Key   string
 	Value string
@@ -126,6 +212,15 @@ func (f ValueField) Set(logger FieldLogger) {
 	logger.SetValue(f.Key, f.Value)
 }
 
+type LazyValueField struct {
+	Key   string
+	Value func() interface{}
+}
+
+func (f LazyValueField) Set(logger FieldLogger) {
+	logger.SetValue(f.Key, f.Value())
+}
+
 type TimeField struct {
 	Key   string
 	Value time.Time
@@ -144,6 +239,15 @@ func (f DurationField) Set(logger FieldLogger) {
 	logger.SetDuration(f.Key, f.Value)
 }
 
+type DurationsField struct {
+	Key    string
+	Values []time.Duration
+}
+
+func (f DurationsField) Set(logger FieldLogger) {
+	logger.SetDurations(f.Key, f.Values)
+}
+
 type JSONField struct {
 	Key   string
 	Value json.RawMessage
@@ -152,3 +256,178 @@ type JSONField struct {
 func (f JSONField) Set(logger FieldLogger) {
 	logger.SetJSON(f.Key, f.Value)
 }
+
+// BytesEncoding defines how binary values of BytesField are rendered in logs and JSON.
+type BytesEncoding int
+
+const (
+	// HexEncoding encodes bytes as a lowercase hexadecimal string.
+	HexEncoding BytesEncoding = iota
+	// Base64Encoding encodes bytes as a standard base64 string with padding.
+	Base64Encoding
+)
+
+// Encode returns the string representation of the bytes in this encoding.
+func (e BytesEncoding) Encode(value []byte) string {
+	if e == Base64Encoding {
+		return base64.StdEncoding.EncodeToString(value)
+	}
+	return hex.EncodeToString(value)
+}
+
+type BytesField struct {
+	Key      string
+	Value    []byte
+	Encoding BytesEncoding
+}
+
+func (f BytesField) Set(logger FieldLogger) {
+	logger.SetBytes(f.Key, f.Value, f.Encoding)
+}
+
+type ErrorField struct {
+	Key   string
+	Value error
+}
+
+func (f ErrorField) Set(logger FieldLogger) {
+	logger.SetError(f.Key, f.Value)
+}
+
+type ErrorsField struct {
+	Key    string
+	Values []error
+}
+
+func (f ErrorsField) Set(logger FieldLogger) {
+	logger.SetErrors(f.Key, f.Values)
+}
+
+// GroupField groups several fields under the key. Loggers of this package write them as a nested
+// object into JSON and as an indented block in the "%+v" output, so keys of the grouped fields
+// do not collide with top-level keys. Other loggers receive every grouped field
+// with the key prefixed by the key of the group and a dot, for example "context.id".
+type GroupField struct {
+	Key    string
+	Fields []Field
+}
+
+func (f GroupField) Set(logger FieldLogger) {
+	if group, ok := logger.(groupLogger); ok {
+		group.setGroup(f.Key, f.Fields)
+		return
+	}
+	for _, field := range f.Fields {
+		field.Set(keyLogger{prefix: f.Key + ".", logger: logger})
+	}
+}
+
+// groupLogger is implemented by internal loggers that are able to write grouped fields.
+type groupLogger interface {
+	setGroup(key string, fields []Field)
+}
+
+// MarkerField marks the error with an attribute that is read back by this package or an adapter,
+// like the status set by WithHTTPStatus or the mark set by Retryable. Loggers receive the underlying
+// field as is. Marker fields are not prefixed by WithKeyPrefix, so functions like HTTPStatus
+// and IsRetryable still find them.
+type MarkerField struct {
+	Field
+}
+
+func (f MarkerField) Set(logger FieldLogger) {
+	if marker, ok := logger.(markerLogger); ok {
+		marker.setMarker(f.Field)
+		return
+	}
+	f.Field.Set(logger)
+}
+
+// IsMarker reports whether the field is a MarkerField. Marker fields are diagnostic attributes
+// of the error, so it can be used to hide them from clients, like in problem details.
+func IsMarker(field Field) bool {
+	_, ok := field.(MarkerField)
+	return ok
+}
+
+// markerLogger is implemented by internal loggers that keep marker fields as they are.
+type markerLogger interface {
+	setMarker(field Field)
+}
+
+// prefixedField sets the field with the prefixed key.
+type prefixedField struct {
+	prefix string
+	field  Field
+}
+
+func (f prefixedField) Set(logger FieldLogger) {
+	f.field.Set(keyLogger{prefix: f.prefix, logger: logger})
+}
+
+// keyLogger adds the prefix and the suffix to every key passed to the underlying logger.
+type keyLogger struct {
+	prefix string
+	suffix string
+	logger FieldLogger
+}
+
+func (l keyLogger) key(key string) string { return l.prefix + key + l.suffix }
+
+func (l keyLogger) SetBool(key string, value bool)      { l.logger.SetBool(l.key(key), value) }
+func (l keyLogger) SetBools(key string, values []bool)  { l.logger.SetBools(l.key(key), values) }
+func (l keyLogger) SetInt(key string, value int)        { l.logger.SetInt(l.key(key), value) }
+func (l keyLogger) SetInts(key string, values []int)    { l.logger.SetInts(l.key(key), values) }
+func (l keyLogger) SetInt32(key string, value int32)    { l.logger.SetInt32(l.key(key), value) }
+func (l keyLogger) SetInt64(key string, value int64)    { l.logger.SetInt64(l.key(key), value) }
+func (l keyLogger) SetUint(key string, value uint)      { l.logger.SetUint(l.key(key), value) }
+func (l keyLogger) SetUint64(key string, value uint64)  { l.logger.SetUint64(l.key(key), value) }
+func (l keyLogger) SetFloat(key string, value float64)  { l.logger.SetFloat(l.key(key), value) }
+func (l keyLogger) SetString(key string, value string)  { l.logger.SetString(l.key(key), value) }
+func (l keyLogger) SetTime(key string, value time.Time) { l.logger.SetTime(l.key(key), value) }
+func (l keyLogger) SetError(key string, value error)    { l.logger.SetError(l.key(key), value) }
+func (l keyLogger) SetStackTrace(trace StackTrace)      { l.logger.SetStackTrace(trace) }
+
+func (l keyLogger) SetFloats(key string, values []float64) {
+	l.logger.SetFloats(l.key(key), values)
+}
+
+func (l keyLogger) SetStrings(key string, values []string) {
+	l.logger.SetStrings(l.key(key), values)
+}
+
+func (l keyLogger) SetValue(key string, value interface{}) {
+	l.logger.SetValue(l.key(key), value)
+}
+
+func (l keyLogger) SetDuration(key string, value time.Duration) {
+	l.logger.SetDuration(l.key(key), value)
+}
+
+func (l keyLogger) SetDurations(key string, values []time.Duration) {
+	l.logger.SetDurations(l.key(key), values)
+}
+
+func (l keyLogger) SetJSON(key string, value json.RawMessage) {
+	l.logger.SetJSON(l.key(key), value)
+}
+
+func (l keyLogger) SetBytes(key string, value []byte, encoding BytesEncoding) {
+	l.logger.SetBytes(l.key(key), value, encoding)
+}
+
+func (l keyLogger) SetErrors(key string, values []error) {
+	l.logger.SetErrors(l.key(key), values)
+}
+
+func (l keyLogger) setGroup(key string, fields []Field) {
+	GroupField{Key: l.key(key), Fields: fields}.Set(l.logger)
+}
+
+func (l keyLogger) setRawString(key string, value string) {
+	if raw, ok := l.logger.(rawValueLogger); ok {
+		raw.setRawString(l.key(key), value)
+	} else {
+		l.logger.SetString(l.key(key), redactionMask.Load().(string))
+	}
+}
@@ -0,0 +1,38 @@
+package grpcadapter
+
+import (
+	"github.com/muonsoft/errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const codeKey = "grpcCode"
+
+// WithCode sets gRPC status code of the error as a field "grpcCode".
+func WithCode(code codes.Code) errors.Option {
+	return errors.Marker(errors.ValueField{Key: codeKey, Value: code})
+}
+
+// Code returns gRPC status code set by WithCode option.
+// If there are several codes in the chain, then the outermost one is returned.
+func Code(err error) (codes.Code, bool) {
+	value, _ := errors.GetField(err, codeKey)
+	code, ok := value.(codes.Code)
+
+	return code, ok
+}
+
+// Status converts the error into gRPC status with the error message and the code
+// set by WithCode option. If there is no code in the chain, then codes.Unknown is used.
+// If err is nil, Status returns nil, that is treated as a status with codes.OK.
+func Status(err error) *status.Status {
+	if err == nil {
+		return nil
+	}
+	code, ok := Code(err)
+	if !ok {
+		code = codes.Unknown
+	}
+
+	return status.New(code, err.Error())
+}
@@ -0,0 +1,57 @@
+package grpcadapter_test
+
+import (
+	"testing"
+
+	"github.com/muonsoft/errors"
+	"github.com/muonsoft/errors/logging/grpcadapter"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestCode(t *testing.T) {
+	err := errors.Wrap(
+		errors.Errorf("find product: %w", errors.Errorf("not found", grpcadapter.WithCode(codes.NotFound))),
+		errors.String("key", "value"),
+	)
+
+	code, ok := grpcadapter.Code(err)
+	if !ok {
+		t.Fatalf("want %#v to have a code", err)
+	}
+	if code != codes.NotFound {
+		t.Errorf("want code %s, got %s", codes.NotFound, code)
+	}
+}
+
+func TestCode_noCode(t *testing.T) {
+	if _, ok := grpcadapter.Code(errors.Errorf("ooh")); ok {
+		t.Error("want error without a code")
+	}
+}
+
+func TestStatus(t *testing.T) {
+	err := errors.Wrap(errors.Errorf("not found", grpcadapter.WithCode(codes.NotFound)))
+
+	st, ok := status.FromError(grpcadapter.Status(err).Err())
+	if !ok {
+		t.Fatal("want gRPC status")
+	}
+	if st.Code() != codes.NotFound {
+		t.Errorf("want code %s, got %s", codes.NotFound, st.Code())
+	}
+	if st.Message() != "not found" {
+		t.Errorf(`want message "not found", got %q`, st.Message())
+	}
+}
+
+func TestStatus_unknown(t *testing.T) {
+	st := grpcadapter.Status(errors.New("ooh"))
+
+	if st.Code() != codes.Unknown {
+		t.Errorf("want code %s, got %s", codes.Unknown, st.Code())
+	}
+	if grpcadapter.Status(nil).Code() != codes.OK {
+		t.Error("want OK code for nil error")
+	}
+}
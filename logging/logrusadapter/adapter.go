@@ -30,15 +30,39 @@ type adapter struct {
 }
 
 func (a *adapter) SetBool(key string, value bool)              { a.log = a.log.WithField(key, value) }
+func (a *adapter) SetBools(key string, values []bool)          { a.log = a.log.WithField(key, values) }
 func (a *adapter) SetInt(key string, value int)                { a.log = a.log.WithField(key, value) }
+func (a *adapter) SetInts(key string, values []int)            { a.log = a.log.WithField(key, values) }
+func (a *adapter) SetInt32(key string, value int32)            { a.log = a.log.WithField(key, value) }
+func (a *adapter) SetInt64(key string, value int64)            { a.log = a.log.WithField(key, value) }
 func (a *adapter) SetUint(key string, value uint)              { a.log = a.log.WithField(key, value) }
+func (a *adapter) SetUint64(key string, value uint64)          { a.log = a.log.WithField(key, value) }
 func (a *adapter) SetFloat(key string, value float64)          { a.log = a.log.WithField(key, value) }
+func (a *adapter) SetFloats(key string, values []float64)      { a.log = a.log.WithField(key, values) }
 func (a *adapter) SetString(key string, value string)          { a.log = a.log.WithField(key, value) }
 func (a *adapter) SetStrings(key string, values []string)      { a.log = a.log.WithField(key, values) }
 func (a *adapter) SetValue(key string, value interface{})      { a.log = a.log.WithField(key, value) }
 func (a *adapter) SetTime(key string, value time.Time)         { a.log = a.log.WithField(key, value) }
 func (a *adapter) SetDuration(key string, value time.Duration) { a.log = a.log.WithField(key, value) }
-func (a *adapter) SetJSON(key string, value json.RawMessage)   { a.log = a.log.WithField(key, value) }
+func (a *adapter) SetDurations(key string, values []time.Duration) {
+	a.log = a.log.WithField(key, values)
+}
+func (a *adapter) SetJSON(key string, value json.RawMessage) { a.log = a.log.WithField(key, value) }
+func (a *adapter) SetError(key string, value error)          { a.log = a.log.WithField(key, value) }
+
+func (a *adapter) SetBytes(key string, value []byte, encoding errors.BytesEncoding) {
+	a.log = a.log.WithField(key, encoding.Encode(value))
+}
+
+func (a *adapter) SetErrors(key string, values []error) {
+	messages := make([]string, len(values))
+	for i, value := range values {
+		if value != nil {
+			messages[i] = value.Error()
+		}
+	}
+	a.log = a.log.WithField(key, messages)
+}
 
 func (a *adapter) SetStackTrace(trace errors.StackTrace) {
 	type Frame struct {
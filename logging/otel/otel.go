@@ -0,0 +1,22 @@
+package otel
+
+import (
+	"context"
+
+	"github.com/muonsoft/errors"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// WithSpanContext sets IDs of the active trace and span from the context
+// as string fields "traceID" and "spanID". If there is no valid span in the context,
+// then no fields are set.
+func WithSpanContext(ctx context.Context) errors.Option {
+	return func(options *errors.Options) {
+		spanContext := trace.SpanContextFromContext(ctx)
+		if !spanContext.IsValid() {
+			return
+		}
+		options.AddField(errors.StringField{Key: "traceID", Value: spanContext.TraceID().String()})
+		options.AddField(errors.StringField{Key: "spanID", Value: spanContext.SpanID().String()})
+	}
+}
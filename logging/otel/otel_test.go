@@ -0,0 +1,37 @@
+package otel_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/muonsoft/errors"
+	"github.com/muonsoft/errors/errorstest"
+	"github.com/muonsoft/errors/logging/otel"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestWithSpanContext(t *testing.T) {
+	spanContext := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID: trace.TraceID{0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07, 0x08, 0x09, 0x0a, 0x0b, 0x0c, 0x0d, 0x0e, 0x0f, 0x10},
+		SpanID:  trace.SpanID{0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07, 0x08},
+	})
+	ctx := trace.ContextWithSpanContext(context.Background(), spanContext)
+
+	err := errors.Errorf("ooh", otel.WithSpanContext(ctx))
+
+	logger := errorstest.NewLogger()
+	errors.Log(err, logger)
+	logger.AssertField(t, "traceID", "0102030405060708090a0b0c0d0e0f10")
+	logger.AssertField(t, "spanID", "0102030405060708")
+}
+
+func TestWithSpanContext_noSpan(t *testing.T) {
+	err := errors.Errorf("ooh", otel.WithSpanContext(context.Background()))
+
+	if _, ok := errors.GetField(err, "traceID"); ok {
+		t.Error("want no trace ID without a span")
+	}
+	if _, ok := errors.GetField(err, "spanID"); ok {
+		t.Error("want no span ID without a span")
+	}
+}
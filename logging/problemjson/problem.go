@@ -0,0 +1,141 @@
+package problemjson
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/muonsoft/errors"
+)
+
+// ContentType is the media type of problem details defined by RFC 7807.
+const ContentType = "application/problem+json"
+
+// standard members of problem details, that cannot be overridden by extension members.
+var standard = map[string]bool{
+	"type":     true,
+	"title":    true,
+	"status":   true,
+	"detail":   true,
+	"instance": true,
+}
+
+// Problem describes an error in the format of RFC 7807. Extensions are marshaled
+// as top-level members of the JSON object along with the standard members.
+type Problem struct {
+	Type       string
+	Title      string
+	Status     int
+	Detail     string
+	Instance   string
+	Extensions map[string]interface{}
+}
+
+// ToProblem builds problem details from the error:
+//   - the error message is used as "detail";
+//   - the status set by errors.WithHTTPStatus is used as "status", by default it is 500;
+//   - the code set by errors.WithCode is used as "type" and "title", by default "type" is "about:blank"
+//     and "title" is the text of the status code;
+//   - other fields of the chain are used as extension members, fields with the keys
+//     of the standard members and marker fields (like the ones set by errors.WithCaller
+//     or errors.Timestamp) are skipped, errors are rendered as their messages.
+//
+// If err is nil, ToProblem returns nil.
+func ToProblem(err error) *Problem {
+	if err == nil {
+		return nil
+	}
+
+	status, ok := errors.HTTPStatus(err)
+	if !ok {
+		status = http.StatusInternalServerError
+	}
+	problem := &Problem{
+		Type:   "about:blank",
+		Title:  http.StatusText(status),
+		Status: status,
+		Detail: err.Error(),
+	}
+	if code, ok := errors.GetCode(err); ok {
+		problem.Type = code
+		problem.Title = code
+	}
+	members := extensions{}
+	for _, field := range errors.Fields(err) {
+		if !errors.IsMarker(field) {
+			field.Set(members)
+		}
+	}
+	if len(members) > 0 {
+		problem.Extensions = members
+	}
+
+	return problem
+}
+
+func (p *Problem) MarshalJSON() ([]byte, error) {
+	data := make(map[string]interface{}, len(p.Extensions)+5)
+	for key, value := range p.Extensions {
+		data[key] = value
+	}
+	data["type"] = p.Type
+	data["title"] = p.Title
+	data["status"] = p.Status
+	data["detail"] = p.Detail
+	if p.Instance != "" {
+		data["instance"] = p.Instance
+	}
+
+	return json.Marshal(data)
+}
+
+// extensions collects fields of the error as extension members. Errors are rendered
+// as their messages, because errors are usually marshaled as "{}".
+type extensions map[string]interface{}
+
+func (e extensions) set(key string, value interface{}) {
+	if !standard[key] {
+		e[key] = value
+	}
+}
+
+func (e extensions) SetBool(key string, value bool)                  { e.set(key, value) }
+func (e extensions) SetBools(key string, values []bool)              { e.set(key, values) }
+func (e extensions) SetInt(key string, value int)                    { e.set(key, value) }
+func (e extensions) SetInts(key string, values []int)                { e.set(key, values) }
+func (e extensions) SetInt32(key string, value int32)                { e.set(key, value) }
+func (e extensions) SetInt64(key string, value int64)                { e.set(key, value) }
+func (e extensions) SetUint(key string, value uint)                  { e.set(key, value) }
+func (e extensions) SetUint64(key string, value uint64)              { e.set(key, value) }
+func (e extensions) SetFloat(key string, value float64)              { e.set(key, value) }
+func (e extensions) SetFloats(key string, values []float64)          { e.set(key, values) }
+func (e extensions) SetString(key string, value string)              { e.set(key, value) }
+func (e extensions) SetStrings(key string, values []string)          { e.set(key, values) }
+func (e extensions) SetValue(key string, value interface{})          { e.set(key, value) }
+func (e extensions) SetTime(key string, value time.Time)             { e.set(key, value) }
+func (e extensions) SetDuration(key string, value time.Duration)     { e.set(key, value) }
+func (e extensions) SetDurations(key string, values []time.Duration) { e.set(key, values) }
+func (e extensions) SetJSON(key string, value json.RawMessage)       { e.set(key, value) }
+func (e extensions) SetStackTrace(trace errors.StackTrace)           {}
+
+func (e extensions) SetBytes(key string, value []byte, encoding errors.BytesEncoding) {
+	e.set(key, value)
+}
+
+func (e extensions) SetError(key string, value error) {
+	if value == nil {
+		e.set(key, nil)
+		return
+	}
+	e.set(key, value.Error())
+}
+
+func (e extensions) SetErrors(key string, values []error) {
+	messages := make([]string, len(values))
+	for i, err := range values {
+		if err != nil {
+			messages[i] = err.Error()
+		}
+	}
+	e.set(key, messages)
+}
@@ -0,0 +1,93 @@
+package problemjson_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/muonsoft/errors"
+	"github.com/muonsoft/errors/logging/grpcadapter"
+	"github.com/muonsoft/errors/logging/problemjson"
+	"google.golang.org/grpc/codes"
+)
+
+func TestToProblem(t *testing.T) {
+	err := errors.Wrap(
+		errors.Errorf("product not found", errors.WithCode("product-not-found"), errors.Int("productID", 123)),
+		errors.WithHTTPStatus(http.StatusNotFound),
+	)
+
+	data, e := json.Marshal(problemjson.ToProblem(err))
+	if e != nil {
+		t.Fatalf("want problem to be marshalable into json: %v", e)
+	}
+	var got map[string]interface{}
+	if e := json.Unmarshal(data, &got); e != nil {
+		t.Fatalf("failed to unmarshal json: %v", e)
+	}
+
+	want := map[string]interface{}{
+		"type":      "product-not-found",
+		"title":     "product-not-found",
+		"status":    float64(http.StatusNotFound),
+		"detail":    "product not found",
+		"productID": float64(123),
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("want problem %v, got %v", want, got)
+	}
+}
+
+func TestToProblem_defaults(t *testing.T) {
+	problem := problemjson.ToProblem(errors.Errorf("ooh"))
+
+	want := &problemjson.Problem{
+		Type:   "about:blank",
+		Title:  "Internal Server Error",
+		Status: http.StatusInternalServerError,
+		Detail: "ooh",
+	}
+	if !reflect.DeepEqual(problem, want) {
+		t.Errorf("want problem %#v, got %#v", want, problem)
+	}
+	if problemjson.ToProblem(nil) != nil {
+		t.Error("want nil problem for nil error")
+	}
+}
+
+func TestToProblem_extensions(t *testing.T) {
+	err := errors.Errorf(
+		"payment failed",
+		errors.Error("cause", errors.New("card declined")),
+		errors.Errors("attempts", []error{errors.New("timeout"), errors.New("card declined")}),
+		errors.WithCaller(),
+		errors.Timestamp(),
+		errors.Retryable(),
+		errors.Timeout(),
+		grpcadapter.WithCode(codes.Unavailable),
+		errors.String("orderID", "abc"),
+	)
+
+	problem := problemjson.ToProblem(err)
+
+	want := map[string]interface{}{
+		"cause":    "card declined",
+		"attempts": []string{"timeout", "card declined"},
+		"orderID":  "abc",
+	}
+	if !reflect.DeepEqual(problem.Extensions, want) {
+		t.Errorf("want extensions %v, got %v", want, problem.Extensions)
+	}
+}
+
+func TestToProblem_userFieldsWithMarkerKeys(t *testing.T) {
+	err := errors.Errorf("ooh", errors.String("caller", "billing"), errors.Int("timestamp", 1700000000))
+
+	problem := problemjson.ToProblem(err)
+
+	want := map[string]interface{}{"caller": "billing", "timestamp": 1700000000}
+	if !reflect.DeepEqual(problem.Extensions, want) {
+		t.Errorf("want extensions %v, got %v", want, problem.Extensions)
+	}
+}
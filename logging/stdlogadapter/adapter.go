@@ -0,0 +1,86 @@
+package stdlogadapter
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/muonsoft/errors"
+)
+
+type Option func(adapter *adapter)
+
+// IncludeStackTrace sets whether the stack trace is printed after the message. By default, it is printed.
+func IncludeStackTrace(include bool) Option {
+	return func(adapter *adapter) {
+		adapter.includeStackTrace = include
+	}
+}
+
+// Log prints the error message with fields formatted as key=value pairs into the standard logger.
+// The stack trace is printed on the following lines indented by a tab.
+func Log(err error, logger *log.Logger, options ...Option) {
+	a := &adapter{log: logger, includeStackTrace: true}
+	for _, setOption := range options {
+		setOption(a)
+	}
+	errors.Log(err, a)
+}
+
+type adapter struct {
+	log               *log.Logger
+	includeStackTrace bool
+	fields            strings.Builder
+	stackTrace        errors.StackTrace
+}
+
+func (a *adapter) SetBool(key string, value bool)              { a.set(key, value) }
+func (a *adapter) SetBools(key string, values []bool)          { a.set(key, values) }
+func (a *adapter) SetInt(key string, value int)                { a.set(key, value) }
+func (a *adapter) SetInts(key string, values []int)            { a.set(key, values) }
+func (a *adapter) SetInt32(key string, value int32)            { a.set(key, value) }
+func (a *adapter) SetInt64(key string, value int64)            { a.set(key, value) }
+func (a *adapter) SetUint(key string, value uint)              { a.set(key, value) }
+func (a *adapter) SetUint64(key string, value uint64)          { a.set(key, value) }
+func (a *adapter) SetFloat(key string, value float64)          { a.set(key, value) }
+func (a *adapter) SetFloats(key string, values []float64)      { a.set(key, values) }
+func (a *adapter) SetString(key string, value string)          { a.set(key, value) }
+func (a *adapter) SetStrings(key string, values []string)      { a.set(key, values) }
+func (a *adapter) SetValue(key string, value interface{})      { a.set(key, value) }
+func (a *adapter) SetTime(key string, value time.Time)         { a.set(key, errors.FormatTime(value)) }
+func (a *adapter) SetDuration(key string, value time.Duration) { a.set(key, value) }
+func (a *adapter) SetDurations(key string, values []time.Duration) {
+	a.set(key, values)
+}
+func (a *adapter) SetJSON(key string, value json.RawMessage) { a.set(key, string(value)) }
+func (a *adapter) SetError(key string, value error)          { a.set(key, value) }
+func (a *adapter) SetErrors(key string, values []error)      { a.set(key, values) }
+func (a *adapter) SetStackTrace(trace errors.StackTrace)     { a.stackTrace = trace }
+
+func (a *adapter) SetBytes(key string, value []byte, encoding errors.BytesEncoding) {
+	a.set(key, encoding.Encode(value))
+}
+
+func (a *adapter) set(key string, value interface{}) {
+	s := fmt.Sprintf("%v", value)
+	if strings.ContainsAny(s, " \t\n\"=") {
+		s = strconv.Quote(s)
+	}
+	a.fields.WriteString(" " + key + "=" + s)
+}
+
+func (a *adapter) Log(message string) {
+	var s strings.Builder
+	s.WriteString(message)
+	s.WriteString(a.fields.String())
+	if a.includeStackTrace {
+		for _, frame := range a.stackTrace {
+			s.WriteString("\n\t" + frame.String())
+		}
+	}
+
+	a.log.Print(s.String())
+}
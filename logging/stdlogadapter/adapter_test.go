@@ -0,0 +1,52 @@
+package stdlogadapter_test
+
+import (
+	"bytes"
+	"log"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/muonsoft/errors"
+	"github.com/muonsoft/errors/logging/stdlogadapter"
+)
+
+func TestLog(t *testing.T) {
+	var buffer bytes.Buffer
+	logger := log.New(&buffer, "", 0)
+	err := errors.Errorf("ooh", errors.String("key", "value"), errors.Int("id", 123), errors.String("phrase", "a b"))
+
+	stdlogadapter.Log(err, logger)
+
+	want := `^ooh key=value id=123 phrase="a b"\n` +
+		`\tgithub.com/muonsoft/errors/logging/stdlogadapter_test.TestLog .+/stdlogadapter/adapter_test.go:17\n`
+	if !regexp.MustCompile(want).MatchString(buffer.String()) {
+		t.Errorf("want log output to match %q, got %q", want, buffer.String())
+	}
+}
+
+func TestLog_withoutStackTrace(t *testing.T) {
+	var buffer bytes.Buffer
+	logger := log.New(&buffer, "", 0)
+	err := errors.Errorf("ooh", errors.String("key", "value"))
+
+	stdlogadapter.Log(err, logger, stdlogadapter.IncludeStackTrace(false))
+
+	if buffer.String() != "ooh key=value\n" {
+		t.Errorf(`want log output "ooh key=value\n", got %q`, buffer.String())
+	}
+}
+
+func TestLog_timeFormat(t *testing.T) {
+	errors.SetTimeFormat(time.DateOnly)
+	defer errors.SetTimeFormat(time.RFC3339)
+	var buffer bytes.Buffer
+	logger := log.New(&buffer, "", 0)
+	err := errors.Errorf("ooh", errors.Time("at", time.Date(2024, 3, 15, 10, 30, 0, 0, time.UTC)))
+
+	stdlogadapter.Log(err, logger, stdlogadapter.IncludeStackTrace(false))
+
+	if buffer.String() != "ooh at=2024-03-15\n" {
+		t.Errorf(`want log output "ooh at=2024-03-15\n", got %q`, buffer.String())
+	}
+}
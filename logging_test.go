@@ -79,3 +79,69 @@ func TestLog_joinedErrors(t *testing.T) {
 	logger.AssertField(t, "key4", "value4")
 	logger.AssertField(t, "key5", "value5")
 }
+
+func TestLogHere(t *testing.T) {
+	logger := errorstest.NewLogger()
+
+	err := errors.Errorf("ooh")
+	errors.LogHere(err, logger)
+
+	logger.AssertMessage(t, "ooh")
+	logger.AssertStackTrace(t, errorstest.StackTrace{
+		{
+			Function: "github.com/muonsoft/errors_test.TestLogHere",
+			File:     ".+errors/logging_test.go",
+			Line:     86,
+		},
+	})
+	trace, ok := logger.Fields["logStackTrace"].(errors.StackTrace)
+	if !ok || len(trace) == 0 {
+		t.Fatalf(`want logger to have a stack trace in field "logStackTrace", got %#v`, logger.Fields["logStackTrace"])
+	}
+	if trace[0].Name() != "github.com/muonsoft/errors_test.TestLogHere" {
+		t.Errorf(`want top frame function "github.com/muonsoft/errors_test.TestLogHere", got "%s"`, trace[0].Name())
+	}
+	if trace[0].Line() != 87 {
+		t.Errorf("want top frame line 87, got %d", trace[0].Line())
+	}
+}
+
+func TestLog_keyPrefix(t *testing.T) {
+	logger := errorstest.NewLogger()
+
+	err := errors.Wrap(
+		errors.Errorf("ooh", errors.Int("id", 1), errors.WithKeyPrefix("db")),
+		errors.WithKeyPrefix("api"),
+		errors.Int("id", 2),
+		errors.String("method", "GET"),
+	)
+	errors.Log(err, logger)
+
+	logger.AssertField(t, "db.id", 1)
+	logger.AssertField(t, "api.id", 2)
+	logger.AssertField(t, "api.method", "GET")
+	if _, ok := logger.Fields["id"]; ok {
+		t.Error(`want no field "id" without a prefix`)
+	}
+}
+
+func TestLog_lazyValue(t *testing.T) {
+	calls := 0
+	err := errors.Wrap(
+		errors.Errorf("ooh", errors.LazyValue("key", func() interface{} {
+			calls++
+			return "value"
+		})),
+		errors.String("outer", "value"),
+	)
+
+	if calls != 0 {
+		t.Fatalf("want value not to be computed before logging, got %d calls", calls)
+	}
+	logger := errorstest.NewLogger()
+	errors.Log(err, logger)
+	if calls != 1 {
+		t.Errorf("want value to be computed once while logging, got %d calls", calls)
+	}
+	logger.AssertField(t, "key", "value")
+}
@@ -0,0 +1,155 @@
+package errors
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+var (
+	messageJSONKey    atomic.Value
+	stackTraceJSONKey atomic.Value
+	timeFormat        atomic.Value
+)
+
+func init() {
+	messageJSONKey.Store("error")
+	stackTraceJSONKey.Store("stackTrace")
+	timeFormat.Store(time.RFC3339)
+}
+
+// SetTimeFormat sets the layout used to render time fields in the "%+v" output, in JSON
+// and by text writers, like LogfmtWriter. The default layout is time.RFC3339.
+// It is safe to call SetTimeFormat concurrently with logging and marshaling errors.
+func SetTimeFormat(layout string) {
+	timeFormat.Store(layout)
+}
+
+// FormatTime formats the time with the layout set by SetTimeFormat. It is intended
+// for logger adapters that render time fields as strings.
+func FormatTime(value time.Time) string {
+	return value.Format(timeFormat.Load().(string))
+}
+
+// SetMessageJSONKey sets the name of the key used for the error message in the JSON
+// representation of errors, including errors nested into joined errors and error fields.
+// The default key is "error". It is safe to call SetMessageJSONKey concurrently with marshaling errors.
+func SetMessageJSONKey(key string) {
+	messageJSONKey.Store(key)
+}
+
+// SetStackTraceJSONKey sets the name of the key used for the stack trace in the JSON
+// representation of errors. The default key is "stackTrace". It is safe to call
+// SetStackTraceJSONKey concurrently with marshaling errors.
+func SetStackTraceJSONKey(key string) {
+	stackTraceJSONKey.Store(key)
+}
+
+// MarshalConfig controls the JSON representation of errors produced by MarshalError.
+type MarshalConfig struct {
+	// IncludeStackTrace enables marshaling of stack traces. If it is false,
+	// only the messages and fields of errors are marshaled.
+	IncludeStackTrace bool
+
+	// MaxFrames limits the number of marshaled frames of every stack trace.
+	// The outermost frames are truncated. Zero means no limit.
+	MaxFrames int
+
+	// MarkTruncated appends the frame with the "(N frames omitted)" function name
+	// to the stack traces truncated by MaxFrames.
+	MarkTruncated bool
+}
+
+// defaultMarshalConfig is used by MarshalJSON methods of errors.
+var defaultMarshalConfig = MarshalConfig{IncludeStackTrace: true}
+
+// MarshalError returns the JSON representation of err according to the config.
+// The result of json.Marshal(err) is the same as of MarshalError with IncludeStackTrace set to true.
+func MarshalError(err error, config MarshalConfig) ([]byte, error) {
+	if err == nil {
+		return []byte("null"), nil
+	}
+
+	return json.Marshal(errorData(err, config))
+}
+
+// stackTraceValue returns the stack trace to be marshaled into JSON according to the config.
+// The stack trace itself is not modified.
+func stackTraceValue(trace StackTrace, config MarshalConfig) interface{} {
+	if config.MaxFrames <= 0 || len(trace) <= config.MaxFrames {
+		return trace
+	}
+
+	frames := make([]interface{}, 0, config.MaxFrames+1)
+	for _, frame := range trace[:config.MaxFrames] {
+		frames = append(frames, frame)
+	}
+	if config.MarkTruncated {
+		frames = append(frames, ResolvedFrame{
+			Function: fmt.Sprintf("(%d frames omitted)", len(trace)-config.MaxFrames),
+		})
+	}
+
+	return frames
+}
+
+// safeValue protects marshaling of an error from a field value that cannot be marshaled,
+// like a channel, a function or a value with a panicking MarshalJSON method. Such a value
+// is replaced by a placeholder string, so the rest of the error is still marshaled.
+type safeValue struct {
+	value interface{}
+}
+
+func (v safeValue) MarshalJSON() (data []byte, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			data, err = json.Marshal(fmt.Sprintf("<error formatting value: %v>", r))
+		}
+	}()
+
+	data, err = json.Marshal(v.value)
+	if err != nil {
+		return json.Marshal(fmt.Sprintf("<error marshaling value: %v>", err))
+	}
+
+	return data, nil
+}
+
+// formatValue formats a field value as the "%v" verb does. A nil pointer is rendered as "<nil>"
+// and a panic of the Error, String or Format method of the value is rendered
+// as "<error formatting value: ...>".
+func formatValue(value interface{}) (s string) {
+	defer func() {
+		if r := recover(); r != nil {
+			s = fmt.Sprintf("<error formatting value: %v>", r)
+		}
+	}()
+
+	if v := reflect.ValueOf(value); v.Kind() == reflect.Ptr && v.IsNil() {
+		return "<nil>"
+	}
+	switch v := value.(type) {
+	case fmt.Formatter:
+		state := &formatState{}
+		v.Format(state, 'v')
+		return state.String()
+	case error:
+		return v.Error()
+	case fmt.Stringer:
+		return v.String()
+	}
+
+	return fmt.Sprintf("%v", value)
+}
+
+// formatState is passed to the Format method of a value formatted by the "%v" verb without flags.
+type formatState struct {
+	strings.Builder
+}
+
+func (s *formatState) Width() (int, bool)     { return 0, false }
+func (s *formatState) Precision() (int, bool) { return 0, false }
+func (s *formatState) Flag(int) bool          { return false }
@@ -0,0 +1,295 @@
+package errors_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/muonsoft/errors"
+)
+
+func TestSetStackTraceJSONKey(t *testing.T) {
+	defer errors.SetStackTraceJSONKey("stackTrace")
+	errors.SetStackTraceJSONKey("stack")
+
+	data, err := json.Marshal(errors.Errorf("ooh", errors.String("key", "value")))
+
+	if err != nil {
+		t.Fatalf("failed to marshal error: %v", err)
+	}
+	var jsonError map[string]json.RawMessage
+	if err := json.Unmarshal(data, &jsonError); err != nil {
+		t.Fatalf("failed to unmarshal json: %v", err)
+	}
+	if _, ok := jsonError["stack"]; !ok {
+		t.Errorf(`want stack trace under the "stack" key, got %s`, data)
+	}
+	if _, ok := jsonError["stackTrace"]; ok {
+		t.Errorf(`want no "stackTrace" key, got %s`, data)
+	}
+}
+
+func TestSetMessageJSONKey(t *testing.T) {
+	defer errors.SetMessageJSONKey("error")
+	errors.SetMessageJSONKey("message")
+
+	data, err := json.Marshal(errors.Errorf("ooh", errors.String("key", "value")))
+
+	if err != nil {
+		t.Fatalf("failed to marshal error: %v", err)
+	}
+	var jsonError map[string]interface{}
+	if err := json.Unmarshal(data, &jsonError); err != nil {
+		t.Fatalf("failed to unmarshal json: %v", err)
+	}
+	if jsonError["message"] != "ooh" {
+		t.Errorf(`want message under the "message" key, got %s`, data)
+	}
+	if _, ok := jsonError["error"]; ok {
+		t.Errorf(`want no "error" key, got %s`, data)
+	}
+	if jsonError["key"] != "value" {
+		t.Errorf(`want fields to be marshaled, got %s`, data)
+	}
+}
+
+func TestMarshalError(t *testing.T) {
+	err := errors.Errorf(
+		"ooh: %w",
+		errors.Errorf("inner", errors.String("innerKey", "innerValue")),
+		errors.String("key", "value"),
+	)
+	tests := []struct {
+		name          string
+		config        errors.MarshalConfig
+		hasStackTrace bool
+	}{
+		{name: "stack trace included", config: errors.MarshalConfig{IncludeStackTrace: true}, hasStackTrace: true},
+		{name: "stack trace omitted", config: errors.MarshalConfig{}, hasStackTrace: false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			data, e := errors.MarshalError(err, test.config)
+
+			if e != nil {
+				t.Fatalf("failed to marshal error: %v", e)
+			}
+			var jsonError map[string]interface{}
+			if e := json.Unmarshal(data, &jsonError); e != nil {
+				t.Fatalf("failed to unmarshal json: %v", e)
+			}
+			if jsonError["error"] != "ooh: inner" {
+				t.Errorf(`want message "ooh: inner", got %s`, data)
+			}
+			if jsonError["key"] != "value" || jsonError["innerKey"] != "innerValue" {
+				t.Errorf(`want fields to be marshaled, got %s`, data)
+			}
+			if _, ok := jsonError["stackTrace"]; ok != test.hasStackTrace {
+				t.Errorf(`want stack trace presence to be %t, got %s`, test.hasStackTrace, data)
+			}
+		})
+	}
+}
+
+func TestMarshalError_defaultIsUnchanged(t *testing.T) {
+	err := errors.Errorf("ooh", errors.String("key", "value"))
+
+	want, e := json.Marshal(err)
+	if e != nil {
+		t.Fatalf("failed to marshal error: %v", e)
+	}
+	got, e := errors.MarshalError(err, errors.MarshalConfig{IncludeStackTrace: true})
+	if e != nil {
+		t.Fatalf("failed to marshal error: %v", e)
+	}
+
+	if string(got) != string(want) {
+		t.Errorf("want %s, got %s", want, got)
+	}
+}
+
+func TestMarshalError_maxFrames(t *testing.T) {
+	err := recursiveError(20)
+	tests := []struct {
+		name       string
+		config     errors.MarshalConfig
+		wantFrames int
+		wantMarker string
+	}{
+		{
+			name:       "capped",
+			config:     errors.MarshalConfig{IncludeStackTrace: true, MaxFrames: 5},
+			wantFrames: 5,
+		},
+		{
+			name:       "capped with marker",
+			config:     errors.MarshalConfig{IncludeStackTrace: true, MaxFrames: 5, MarkTruncated: true},
+			wantFrames: 6,
+			wantMarker: fmt.Sprintf("(%d frames omitted)", len(stackTraceOf(t, err))-5),
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			data, e := errors.MarshalError(err, test.config)
+
+			if e != nil {
+				t.Fatalf("failed to marshal error: %v", e)
+			}
+			var jsonError struct {
+				StackTrace errors.ResolvedStackTrace `json:"stackTrace"`
+			}
+			if e := json.Unmarshal(data, &jsonError); e != nil {
+				t.Fatalf("failed to unmarshal json: %v", e)
+			}
+			if len(jsonError.StackTrace) != test.wantFrames {
+				t.Fatalf("want %d frames, got %d", test.wantFrames, len(jsonError.StackTrace))
+			}
+			if test.wantMarker != "" && jsonError.StackTrace[test.wantFrames-1].Function != test.wantMarker {
+				t.Errorf("want last frame to be %q, got %q", test.wantMarker, jsonError.StackTrace[test.wantFrames-1].Function)
+			}
+			if len(stackTraceOf(t, err)) <= 5 {
+				t.Errorf("want in-memory stack trace not to be truncated")
+			}
+		})
+	}
+}
+
+func TestBytesField_MarshalJSON(t *testing.T) {
+	value := []byte{0x00, 0x01, 0xab, 0xff}
+	err := errors.Errorf("ooh", errors.Bytes("hex", value), errors.BytesBase64("base64", value))
+
+	data, e := json.Marshal(err)
+	if e != nil {
+		t.Fatalf("failed to marshal error: %v", e)
+	}
+	var jsonError struct {
+		Hex    string `json:"hex"`
+		Base64 []byte `json:"base64"`
+	}
+	if e := json.Unmarshal(data, &jsonError); e != nil {
+		t.Fatalf("failed to unmarshal json: %v", e)
+	}
+
+	if jsonError.Hex != "0001abff" {
+		t.Errorf(`want hex value "0001abff", got %q`, jsonError.Hex)
+	}
+	if string(jsonError.Base64) != string(value) {
+		t.Errorf("want base64 value to be decoded into %v, got %v", value, jsonError.Base64)
+	}
+	if formatted := fmt.Sprintf("%+v", err); !strings.Contains(formatted, "\nhex: 0001abff\nbase64: AAGr/w==") {
+		t.Errorf("want encoded values in format output, got %q", formatted)
+	}
+}
+
+type panickingValue struct{}
+
+func (panickingValue) String() string               { panic("not initialized") }
+func (panickingValue) MarshalJSON() ([]byte, error) { panic("not initialized") }
+
+func TestPanickingValue(t *testing.T) {
+	err := errors.Errorf("ooh", errors.Value("value", panickingValue{}), errors.String("key", "value"))
+
+	formatted := fmt.Sprintf("%+v", err)
+	data, e := json.Marshal(err)
+
+	if !strings.Contains(formatted, "\nvalue: <error formatting value: not initialized>\n") {
+		t.Errorf("want placeholder in format output, got %q", formatted)
+	}
+	if e != nil {
+		t.Fatalf("failed to marshal error: %v", e)
+	}
+	var jsonError map[string]interface{}
+	if e := json.Unmarshal(data, &jsonError); e != nil {
+		t.Fatalf("failed to unmarshal json: %v", e)
+	}
+	if jsonError["value"] != "<error formatting value: not initialized>" || jsonError["key"] != "value" {
+		t.Errorf("want placeholder in JSON output, got %s", data)
+	}
+}
+
+func TestUnmarshalableValue(t *testing.T) {
+	err := errors.Errorf(
+		"ooh",
+		errors.Value("channel", make(chan int)),
+		errors.Value("func", func() {}),
+		errors.String("key", "value"),
+	)
+
+	data, e := json.Marshal(err)
+
+	if e != nil {
+		t.Fatalf("want error to be marshaled, got %v", e)
+	}
+	var jsonError map[string]interface{}
+	if e := json.Unmarshal(data, &jsonError); e != nil {
+		t.Fatalf("failed to unmarshal json: %v", e)
+	}
+	for _, key := range []string{"channel", "func"} {
+		placeholder, _ := jsonError[key].(string)
+		if !strings.HasPrefix(placeholder, "<error marshaling value: json: unsupported type: ") {
+			t.Errorf("want placeholder for %s value, got %s", key, data)
+		}
+	}
+	if jsonError["key"] != "value" || jsonError["error"] != "ooh" {
+		t.Errorf("want other fields to be marshaled, got %s", data)
+	}
+}
+
+func TestSetTimeFormat(t *testing.T) {
+	defer errors.SetTimeFormat(time.RFC3339)
+	errors.SetTimeFormat("2006-01-02 15:04")
+	err := errors.Errorf("ooh", errors.Time("time", time.Date(2024, 5, 1, 10, 30, 0, 0, time.UTC)))
+
+	formatted := fmt.Sprintf("%+v", err)
+	data, e := json.Marshal(err)
+
+	if !strings.Contains(formatted, "\ntime: 2024-05-01 10:30\n") {
+		t.Errorf("want custom time format in format output, got %q", formatted)
+	}
+	if e != nil {
+		t.Fatalf("failed to marshal error: %v", e)
+	}
+	if !strings.Contains(string(data), `"time":"2024-05-01 10:30"`) {
+		t.Errorf("want custom time format in JSON, got %s", data)
+	}
+}
+
+type nilStringer struct {
+	name string
+}
+
+func (s *nilStringer) String() string { return s.name }
+
+func TestNilStringerValue(t *testing.T) {
+	var value *nilStringer
+	err := errors.Errorf("ooh", errors.Value("value", value), errors.String("key", "value"))
+
+	formatted := fmt.Sprintf("%+v", err)
+
+	if !strings.Contains(formatted, "\nvalue: <nil>\nkey: value\n") {
+		t.Errorf("want typed nil values to be formatted as <nil>, got %q", formatted)
+	}
+}
+
+type panickingFormatter struct{}
+
+func (panickingFormatter) Format(fmt.State, rune) { panic("not initialized") }
+
+func TestFormatValue_panicNotation(t *testing.T) {
+	err := errors.Errorf(
+		"ooh",
+		errors.Value("text", "%!v(PANIC=String method: boom)"),
+		errors.Value("formatter", panickingFormatter{}),
+	)
+
+	formatted := fmt.Sprintf("%+v", err)
+
+	if !strings.Contains(formatted, "\ntext: %!v(PANIC=String method: boom)\n") {
+		t.Errorf("want string value to be kept as is, got %q", formatted)
+	}
+	if !strings.Contains(formatted, "\nformatter: <error formatting value: not initialized>\n") {
+		t.Errorf("want placeholder for panicking formatter, got %q", formatted)
+	}
+}
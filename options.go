@@ -3,11 +3,20 @@ package errors
 import (
 	"encoding/json"
 	"fmt"
+	"net"
+	"sort"
 	"time"
 )
 
 type Options struct {
 	skipCallers int
+	stackDepth  int
+	topFrames   int
+	trimmed     []string
+	skipUntil   []string
+	withCaller  bool
+	noStack     bool
+	keyPrefix   string
 	fields      []Field
 }
 
@@ -31,30 +40,155 @@ func SkipCallers(skip int) Option {
 	}
 }
 
+// StackDepth sets the maximum number of frames captured in a stack trace.
+// By default, 32 frames are captured. Values less than 1 are treated as 1.
+func StackDepth(n int) Option {
+	return func(options *Options) {
+		if n < 1 {
+			n = 1
+		}
+		options.stackDepth = n
+	}
+}
+
+// TopFrames limits the stack trace passed to loggers by Log function and marshaled into JSON
+// to the innermost n frames, so logs are not bloated by full stack traces. The whole stack trace
+// is still recorded and available by GetStackTrace function and by the "%+v" verb.
+// Values less than 1 mean all frames.
+func TopFrames(n int) Option {
+	return func(options *Options) {
+		options.topFrames = n
+	}
+}
+
+// TrimStackBelow drops frames of functions from the package with the given import path
+// (and its subpackages) from the captured stack trace. It can be used to remove noise,
+// like frames of bootstrap code or of a testing framework. The option can be used several times.
+func TrimStackBelow(pkgPath string) Option {
+	return func(options *Options) {
+		options.trimmed = append(options.trimmed, pkgPath)
+	}
+}
+
+// SkipUntil drops leading frames of the captured stack trace while their function names
+// start with the given prefix, so the stack trace starts at the first frame that does not match it.
+// Unlike SkipCallers, it does not depend on the exact number of helper functions and closures,
+// so it is robust to refactoring. For example, SkipUntil("example.com/app/db.") drops frames
+// of the db package helpers that wrapped the error. The option can be used several times.
+func SkipUntil(funcNamePrefix string) Option {
+	return func(options *Options) {
+		options.skipUntil = append(options.skipUntil, funcNamePrefix)
+	}
+}
+
+// WithCaller adds a string field "caller" with the name of the function that created the error,
+// respecting SkipCaller and SkipCallers options. It is cheaper than reading the whole stack trace
+// when only the origin of the error matters.
+func WithCaller() Option {
+	return func(options *Options) {
+		options.withCaller = true
+	}
+}
+
+// NoStack disables recording of a stack trace by Wrap, Errorf and Wrapf.
+// The error still has fields, but a stack trace will be recorded by the next
+// wrapping function called without this option.
+func NoStack() Option {
+	return func(options *Options) {
+		options.noStack = true
+	}
+}
+
+// WithKeyPrefix prefixes keys of all fields added by the same call of Wrap or Errorf.
+// The prefix is joined with a key by a dot, for example "db.id". Fields of wrapped errors
+// are not affected, so it helps to avoid collisions of keys set at different layers.
+// Marker fields (like the ones set by WithHTTPStatus or Retryable) are not prefixed.
+func WithKeyPrefix(prefix string) Option {
+	return func(options *Options) {
+		options.keyPrefix = prefix
+	}
+}
+
+// WithFields adds several fields at once. It is useful when fields are built
+// dynamically, for example from a map.
+func WithFields(fields ...Field) Option {
+	return func(options *Options) {
+		for _, field := range fields {
+			options.AddField(field)
+		}
+	}
+}
+
+// OptionIf applies the option only if cond is true, otherwise it does nothing.
+// It helps to enrich an error conditionally inline, for example,
+// OptionIf(id != 0, Int("id", id)).
+func OptionIf(cond bool, option Option) Option {
+	if cond {
+		return option
+	}
+	return func(options *Options) {}
+}
+
 func Bool(key string, value bool) Option {
 	return func(options *Options) {
 		options.AddField(BoolField{Key: key, Value: value})
 	}
 }
 
+func Bools(key string, values []bool) Option {
+	return func(options *Options) {
+		options.AddField(BoolsField{Key: key, Values: values})
+	}
+}
+
 func Int(key string, value int) Option {
 	return func(options *Options) {
 		options.AddField(IntField{Key: key, Value: value})
 	}
 }
 
+func Ints(key string, values []int) Option {
+	return func(options *Options) {
+		options.AddField(IntsField{Key: key, Values: values})
+	}
+}
+
+func Int32(key string, value int32) Option {
+	return func(options *Options) {
+		options.AddField(Int32Field{Key: key, Value: value})
+	}
+}
+
+func Int64(key string, value int64) Option {
+	return func(options *Options) {
+		options.AddField(Int64Field{Key: key, Value: value})
+	}
+}
+
 func Uint(key string, value uint) Option {
 	return func(options *Options) {
 		options.AddField(UintField{Key: key, Value: value})
 	}
 }
 
+func Uint64(key string, value uint64) Option {
+	return func(options *Options) {
+		options.AddField(Uint64Field{Key: key, Value: value})
+	}
+}
+
 func Float(key string, value float64) Option {
 	return func(options *Options) {
 		options.AddField(FloatField{Key: key, Value: value})
 	}
 }
 
+func Floats(key string, values []float64) Option {
+	return func(options *Options) {
+		options.AddField(FloatsField{Key: key, Values: values})
+	}
+}
+
 func String(key string, value string) Option {
 	return func(options *Options) {
 		options.AddField(StringField{Key: key, Value: value})
@@ -65,6 +199,33 @@ func Stringer(key string, value fmt.Stringer) Option {
 	return String(key, value.String())
 }
 
+// Stringers adds a strings field with the result of the String method of every value.
+// Nil values of the slice are stored as "<nil>".
+func Stringers(key string, values []fmt.Stringer) Option {
+	strs := make([]string, len(values))
+	for i, value := range values {
+		if value == nil {
+			strs[i] = "<nil>"
+		} else {
+			strs[i] = value.String()
+		}
+	}
+
+	return Strings(key, strs)
+}
+
+// Formatter adds a string field with the value formatted by the "%v" verb.
+// The value is formatted at the point the option is created.
+func Formatter(key string, value fmt.Formatter) Option {
+	return String(key, fmt.Sprintf("%v", value))
+}
+
+// GoStringer adds a string field with the Go syntax representation of the value,
+// as it is formatted by the "%#v" verb. The value is formatted at the point the option is created.
+func GoStringer(key string, value fmt.GoStringer) Option {
+	return String(key, value.GoString())
+}
+
 func Strings(key string, values []string) Option {
 	return func(options *Options) {
 		options.AddField(StringsField{Key: key, Values: values})
@@ -77,6 +238,76 @@ func Value(key string, value interface{}) Option {
 	}
 }
 
+// WithValues adds a value field for every entry of the map, for example, from request metadata.
+// Fields are added in the sorted order of keys, so the output is deterministic.
+// As for Value option, values are stored as is and marshaled into JSON by the standard rules.
+func WithValues(values map[string]interface{}) Option {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	return func(options *Options) {
+		for _, key := range keys {
+			options.AddField(ValueField{Key: key, Value: values[key]})
+		}
+	}
+}
+
+// Marker adds the field as a MarkerField. It is made for packages that mark errors
+// with their own attributes, like the gRPC status code.
+func Marker(field Field) Option {
+	return func(options *Options) {
+		options.AddField(MarkerField{Field: field})
+	}
+}
+
+// Group adds the fields grouped under the name. In JSON they are written as a nested object,
+// for example {"context": {"id": 1}}, in the "%+v" output as an indented block.
+// See GroupField for details.
+func Group(name string, fields ...Field) Option {
+	return func(options *Options) {
+		options.AddField(GroupField{Key: name, Fields: fields})
+	}
+}
+
+// Bytes adds a binary value field, that is rendered as a hexadecimal string in logs and JSON.
+func Bytes(key string, value []byte) Option {
+	return func(options *Options) {
+		options.AddField(BytesField{Key: key, Value: value, Encoding: HexEncoding})
+	}
+}
+
+// BytesBase64 adds a binary value field, that is rendered as a base64 string in logs and JSON.
+func BytesBase64(key string, value []byte) Option {
+	return func(options *Options) {
+		options.AddField(BytesField{Key: key, Value: value, Encoding: Base64Encoding})
+	}
+}
+
+// IP adds a string field with the IP address formatted by net.IP.String method,
+// for example "192.0.2.1" or "2001:db8::1".
+func IP(key string, value net.IP) Option {
+	return String(key, value.String())
+}
+
+// IPNet adds a string field with the network in CIDR notation formatted by net.IPNet.String method,
+// for example "192.0.2.0/24".
+func IPNet(key string, value *net.IPNet) Option {
+	return String(key, value.String())
+}
+
+// LazyValue adds a field with a value computed by fn only when the error is logged,
+// formatted or marshaled, so expensive values are not computed for errors that are never logged.
+// The fn may be called more than once (for example, for both JSON and text output),
+// so it should be side-effect-free.
+func LazyValue(key string, fn func() interface{}) Option {
+	return func(options *Options) {
+		options.AddField(LazyValueField{Key: key, Value: fn})
+	}
+}
+
 func Time(key string, value time.Time) Option {
 	return func(options *Options) {
 		options.AddField(TimeField{Key: key, Value: value})
@@ -89,16 +320,46 @@ func Duration(key string, value time.Duration) Option {
 	}
 }
 
+func Durations(key string, values []time.Duration) Option {
+	return func(options *Options) {
+		options.AddField(DurationsField{Key: key, Values: values})
+	}
+}
+
 func JSON(key string, value json.RawMessage) Option {
 	return func(options *Options) {
 		options.AddField(JSONField{Key: key, Value: value})
 	}
 }
 
+// Error adds a related error as a field. Unlike wrapping, the error is not added to the chain.
+func Error(key string, value error) Option {
+	return func(options *Options) {
+		options.AddField(ErrorField{Key: key, Value: value})
+	}
+}
+
+// Errors adds a list of related errors as a field. Unlike wrapping, the errors are not added to the chain.
+func Errors(key string, values []error) Option {
+	return func(options *Options) {
+		options.AddField(ErrorsField{Key: key, Values: values})
+	}
+}
+
 func newOptions(options ...Option) *Options {
 	opts := &Options{}
 	for _, set := range options {
 		set(opts)
 	}
+	if opts.withCaller {
+		opts.AddField(MarkerField{Field: StringField{Key: "caller", Value: callerName(opts.skipCallers)}})
+	}
+	if opts.keyPrefix != "" {
+		for i, field := range opts.fields {
+			if _, ok := field.(MarkerField); !ok {
+				opts.fields[i] = prefixedField{prefix: opts.keyPrefix + ".", field: field}
+			}
+		}
+	}
 	return opts
 }
@@ -0,0 +1,253 @@
+package errors_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/muonsoft/errors"
+	"github.com/muonsoft/errors/errorstest"
+)
+
+func TestIP(t *testing.T) {
+	_, network, _ := net.ParseCIDR("2001:db8::/32")
+	err := errors.Errorf(
+		"ooh",
+		errors.IP("ipv4", net.ParseIP("192.0.2.1")),
+		errors.IP("ipv6", net.ParseIP("2001:0db8:0000:0000:0000:0000:0000:0001")),
+		errors.IPNet("network", network),
+	)
+
+	logger := errorstest.NewLogger()
+	errors.Log(err, logger)
+
+	logger.AssertField(t, "ipv4", "192.0.2.1")
+	logger.AssertField(t, "ipv6", "2001:db8::1")
+	logger.AssertField(t, "network", "2001:db8::/32")
+}
+
+type id int
+
+func (i id) String() string { return fmt.Sprintf("id-%d", int(i)) }
+
+func TestStringers(t *testing.T) {
+	err := errors.Errorf("ooh", errors.Stringers("ids", []fmt.Stringer{id(1), nil, id(2)}))
+
+	logger := errorstest.NewLogger()
+	errors.Log(err, logger)
+
+	logger.AssertField(t, "ids", []string{"id-1", "<nil>", "id-2"})
+	if formatted := fmt.Sprintf("%+v", err); !strings.Contains(formatted, "\nids: id-1, <nil>, id-2\n") {
+		t.Errorf("want joined values in format output, got %q", formatted)
+	}
+}
+
+func TestWithCaller(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{
+			name: "errorf",
+			err:  errors.Errorf("ooh", errors.WithCaller()),
+			want: "github.com/muonsoft/errors_test.TestWithCaller",
+		},
+		{
+			name: "wrap error with stack",
+			err:  errors.Wrap(errors.Errorf("ooh"), errors.WithCaller()),
+			want: "github.com/muonsoft/errors_test.TestWithCaller",
+		},
+		{
+			name: "skip caller",
+			err:  newErrorWithCaller(),
+			want: "github.com/muonsoft/errors_test.TestWithCaller",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			logger := errorstest.NewLogger()
+			errors.Log(test.err, logger)
+
+			logger.AssertField(t, "caller", test.want)
+		})
+	}
+}
+
+//go:noinline
+func newErrorWithCaller() error {
+	return errors.Errorf("ooh", errors.WithCaller(), errors.SkipCaller())
+}
+
+func TestTopFrames(t *testing.T) {
+	for _, n := range []int{1, 2} {
+		t.Run(fmt.Sprintf("%d frames", n), func(t *testing.T) {
+			err := errors.Wrap(errors.Errorf("ooh", errors.TopFrames(n)))
+
+			logger := errorstest.NewLogger()
+			errors.Log(err, logger)
+			var data struct {
+				StackTrace []errors.ResolvedFrame `json:"stackTrace"`
+			}
+			jsonData, e := json.Marshal(err)
+			if e != nil {
+				t.Fatalf("want error to be marshaled into json: %v", e)
+			}
+			if e := json.Unmarshal(jsonData, &data); e != nil {
+				t.Fatalf("failed to unmarshal json: %v", e)
+			}
+
+			if len(logger.StackTrace) != n {
+				t.Errorf("want logger to receive %d frames, got %d", n, len(logger.StackTrace))
+			}
+			if len(data.StackTrace) != n {
+				t.Errorf("want JSON to contain %d frames, got %d", n, len(data.StackTrace))
+			}
+			if name := logger.StackTrace[0].Name(); name != "github.com/muonsoft/errors_test.TestTopFrames.func1" {
+				t.Errorf("want innermost frame to be kept, got %s", name)
+			}
+			if trace, _ := errors.GetStackTrace(err); len(trace) <= n {
+				t.Errorf("want full stack trace to be available, got %d frames", len(trace))
+			}
+		})
+	}
+}
+
+func TestTopFrames_allFrames(t *testing.T) {
+	err := errors.Errorf("ooh", errors.TopFrames(0))
+
+	logger := errorstest.NewLogger()
+	errors.Log(err, logger)
+
+	if trace, _ := errors.GetStackTrace(err); len(logger.StackTrace) != len(trace) {
+		t.Errorf("want logger to receive all %d frames, got %d", len(trace), len(logger.StackTrace))
+	}
+}
+
+func TestWithValues(t *testing.T) {
+	err := errors.Errorf("ooh", errors.WithValues(map[string]interface{}{
+		"requestID": "abc",
+		"attempt":   2,
+		"cached":    true,
+	}))
+
+	formatted := fmt.Sprintf("%+v", err)
+
+	want := "ooh\nattempt: 2\ncached: true\nrequestID: abc\n"
+	if !strings.HasPrefix(formatted, want) {
+		t.Errorf("want fields in sorted order %q, got %q", want, formatted)
+	}
+	logger := errorstest.NewLogger()
+	errors.Log(err, logger)
+	logger.AssertField(t, "attempt", 2)
+}
+
+func TestOptionIf(t *testing.T) {
+	for _, id := range []int{0, 123} {
+		t.Run(fmt.Sprintf("id %d", id), func(t *testing.T) {
+			err := errors.Errorf("ooh", errors.OptionIf(id != 0, errors.Int("id", id)), errors.String("key", "value"))
+
+			logger := errorstest.NewLogger()
+			errors.Log(err, logger)
+
+			logger.AssertField(t, "key", "value")
+			if _, exists := logger.Fields["id"]; exists != (id != 0) {
+				t.Errorf(`want field "id" to be present only for non-zero id, got %v`, logger.Fields)
+			}
+		})
+	}
+}
+
+func TestGroup(t *testing.T) {
+	err := errors.Errorf(
+		"ooh",
+		errors.Int("id", 1),
+		errors.Group("context", errors.IntField{Key: "id", Value: 2}, errors.StringField{Key: "user", Value: "alice"}),
+	)
+
+	t.Run("JSON", func(t *testing.T) {
+		jsonData, e := json.Marshal(err)
+		if e != nil {
+			t.Fatalf("want error to be marshaled into json: %v", e)
+		}
+		var data struct {
+			ID      int `json:"id"`
+			Context struct {
+				ID   int    `json:"id"`
+				User string `json:"user"`
+			} `json:"context"`
+		}
+		if e := json.Unmarshal(jsonData, &data); e != nil {
+			t.Fatalf("failed to unmarshal json: %v", e)
+		}
+		if data.ID != 1 || data.Context.ID != 2 || data.Context.User != "alice" {
+			t.Errorf("want nested object under the group key, got %s", jsonData)
+		}
+	})
+	t.Run("text", func(t *testing.T) {
+		formatted := fmt.Sprintf("%+v", err)
+
+		want := "ooh\nid: 1\ncontext:\n  id: 2\n  user: alice\n"
+		if !strings.HasPrefix(formatted, want) {
+			t.Errorf("want indented group %q, got %q", want, formatted)
+		}
+	})
+	t.Run("logger", func(t *testing.T) {
+		logger := errorstest.NewLogger()
+		errors.Log(err, logger)
+
+		logger.AssertField(t, "id", 1)
+		logger.AssertField(t, "context.id", 2)
+		logger.AssertField(t, "context.user", "alice")
+	})
+}
+
+func TestGroup_severalLayers(t *testing.T) {
+	err := errors.Wrap(
+		errors.Errorf("ooh", errors.Group("context", errors.IntField{Key: "id", Value: 1})),
+		errors.Group("context", errors.StringField{Key: "user", Value: "alice"}),
+	)
+
+	jsonData, e := json.Marshal(err)
+	if e != nil {
+		t.Fatalf("want error to be marshaled into json: %v", e)
+	}
+	var data struct {
+		Context map[string]interface{} `json:"context"`
+	}
+	if e := json.Unmarshal(jsonData, &data); e != nil {
+		t.Fatalf("failed to unmarshal json: %v", e)
+	}
+
+	want := map[string]interface{}{"id": float64(1), "user": "alice"}
+	if len(data.Context) != len(want) || data.Context["id"] != want["id"] || data.Context["user"] != want["user"] {
+		t.Errorf("want groups of several layers to be merged into %v, got %s", want, jsonData)
+	}
+}
+
+func TestWithKeyPrefix_markers(t *testing.T) {
+	err := errors.Wrap(
+		errors.New("ooh"),
+		errors.WithKeyPrefix("db"),
+		errors.WithHTTPStatus(404),
+		errors.Retryable(),
+		errors.WithCode("not-found"),
+		errors.String("table", "users"),
+	)
+
+	if !errors.IsRetryable(err) {
+		t.Error("want error to be retryable")
+	}
+	if status, ok := errors.HTTPStatus(err); !ok || status != 404 {
+		t.Errorf("want http status 404, got %d", status)
+	}
+	if code, ok := errors.GetCode(err); !ok || code != "not-found" {
+		t.Errorf(`want code "not-found", got %q`, code)
+	}
+	logger := errorstest.NewLogger()
+	errors.Log(err, logger)
+	logger.AssertField(t, "db.table", "users")
+	logger.AssertField(t, "httpStatus", 404)
+}
@@ -0,0 +1,36 @@
+package errors
+
+// Must returns value if err is nil and panics otherwise. The panic value is err wrapped
+// with a stack trace pointing to the place Must was called, so it still can be handled
+// by errors.As and errors.Is after recovering. It is made for initialization code and tests.
+func Must[T any](value T, err error) T {
+	if err != nil {
+		panic(Wrap(err, SkipCaller()))
+	}
+
+	return value
+}
+
+// Recover converts the value returned by the built-in recover function into an error
+// with a stack trace pointing to the place Recover was called. It is made to be used in a
+// deferred function, so the stack trace also contains the place where the panic happened.
+// It returns nil if recovered is nil. Errors are wrapped by Wrap and values
+// of other types are formatted by Errorf("%v", recovered). Options can be used
+// to set fields of the error.
+func Recover(recovered interface{}, options ...Option) error {
+	if recovered == nil {
+		return nil
+	}
+	options = append(options, SkipCaller())
+	if err, ok := recovered.(error); ok {
+		return Wrap(err, options...)
+	}
+
+	argsAndOptions := make([]interface{}, 0, len(options)+1)
+	argsAndOptions = append(argsAndOptions, recovered)
+	for _, option := range options {
+		argsAndOptions = append(argsAndOptions, option)
+	}
+
+	return Errorf("%v", argsAndOptions...)
+}
@@ -0,0 +1,99 @@
+package errors_test
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/muonsoft/errors"
+)
+
+func TestMust(t *testing.T) {
+	value := errors.Must(strconv.Atoi("123"))
+
+	if value != 123 {
+		t.Errorf("want value 123, got %d", value)
+	}
+}
+
+func TestMust_panic(t *testing.T) {
+	defer func() {
+		err, ok := recover().(error)
+		if !ok {
+			t.Fatal("want panic with error")
+		}
+		if _, ok := errors.As[*strconv.NumError](err); !ok {
+			t.Errorf("want %#v to wrap *strconv.NumError", err)
+		}
+		assertSingleStack(t, err)
+		stacked, ok := errors.As[errors.StackTracer](err)
+		if !ok {
+			t.Fatalf("want %#v to have a stack trace", err)
+		}
+		assertFormatRegexp(t, stacked.StackTrace()[0], "%+v", "github.com/muonsoft/errors_test.TestMust_panic\n"+
+			"\t.+/errors/panics_test.go:36")
+	}()
+
+	errors.Must(strconv.Atoi("ooh"))
+}
+
+func TestRecover_nil(t *testing.T) {
+	if err := errors.Recover(nil); err != nil {
+		t.Errorf("want nil error, got %v", err)
+	}
+}
+
+func TestRecover(t *testing.T) {
+	tests := []struct {
+		name        string
+		panicValue  interface{}
+		wantMessage string
+	}{
+		{name: "error", panicValue: errTest, wantMessage: "test error"},
+		{name: "string", panicValue: "ooh", wantMessage: "ooh"},
+		{name: "integer", panicValue: 123, wantMessage: "123"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := recoverPanic(test.panicValue)
+
+			if err == nil {
+				t.Fatal("want error")
+			}
+			if err.Error() != test.wantMessage {
+				t.Errorf("want message %q, got %q", test.wantMessage, err.Error())
+			}
+			if panicErr, ok := test.panicValue.(error); ok && !errors.Is(err, panicErr) {
+				t.Errorf("want %#v to wrap %#v", err, panicErr)
+			}
+			if value, _ := errors.GetField(err, "key"); value != "value" {
+				t.Errorf(`want %#v to have field "key"`, err)
+			}
+			assertSingleStack(t, err)
+			stacked, ok := errors.As[errors.StackTracer](err)
+			if !ok {
+				t.Fatalf("want %#v to have a stack trace", err)
+			}
+			found := false
+			for _, frame := range stacked.StackTrace() {
+				if frame.Name() == "github.com/muonsoft/errors_test.panicWith" {
+					found = true
+				}
+			}
+			if !found {
+				t.Errorf("want stack trace to contain the place of panic: %v", stacked.StackTrace().Strings())
+			}
+		})
+	}
+}
+
+func recoverPanic(value interface{}) (err error) {
+	defer func() {
+		err = errors.Recover(recover(), errors.String("key", "value"))
+	}()
+	panicWith(value)
+	return nil
+}
+
+func panicWith(value interface{}) {
+	panic(value)
+}
@@ -0,0 +1,50 @@
+package errors
+
+import "sync/atomic"
+
+var redactionMask atomic.Value
+
+func init() {
+	redactionMask.Store("***")
+}
+
+// SetRedactionMask sets the placeholder used instead of values of redacted fields.
+// The default mask is "***". It is safe to call SetRedactionMask concurrently with logging errors.
+func SetRedactionMask(mask string) {
+	redactionMask.Store(mask)
+}
+
+// Redacted adds a field with a sensitive value, like an email or a token. The value is stored raw,
+// but it is replaced by the redaction mask in logs, in JSON and in the "%+v" output.
+// The raw value can be read by GetField.
+func Redacted(key string, value string) Option {
+	return func(options *Options) {
+		options.AddField(RedactedField{Key: key, Value: value})
+	}
+}
+
+type RedactedField struct {
+	Key   string
+	Value string
+}
+
+func (f RedactedField) Set(logger FieldLogger) {
+	if raw, ok := logger.(rawValueLogger); ok {
+		raw.setRawString(f.Key, f.Value)
+		return
+	}
+	logger.SetString(f.Key, redactionMask.Load().(string))
+}
+
+// rawValueLogger is implemented by internal loggers that are allowed to read raw values
+// of redacted fields.
+type rawValueLogger interface {
+	setRawString(key string, value string)
+}
+
+// rawKeyValueLogger works like keyValueLogger, but passes raw values of redacted fields.
+type rawKeyValueLogger struct {
+	keyValueLogger
+}
+
+func (l rawKeyValueLogger) setRawString(key string, value string) { l.keyValueLogger(key, value) }
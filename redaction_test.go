@@ -0,0 +1,61 @@
+package errors_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/muonsoft/errors"
+	"github.com/muonsoft/errors/errorstest"
+)
+
+func TestRedacted(t *testing.T) {
+	err := errors.Wrap(
+		errors.Errorf("access denied", errors.Redacted("email", "user@example.com")),
+		errors.Redacted("token", "secret-token"),
+	)
+
+	jsonData, e := json.Marshal(err)
+	if e != nil {
+		t.Fatalf("expected %#v to be marshalable into json: %v", err, e)
+	}
+	formatted := fmt.Sprintf("%+v", err)
+	for _, output := range []string{string(jsonData), formatted} {
+		if strings.Contains(output, "user@example.com") || strings.Contains(output, "secret-token") {
+			t.Errorf("want raw values to be redacted, got %s", output)
+		}
+	}
+	if !strings.Contains(formatted, "email: ***") || !strings.Contains(formatted, "token: ***") {
+		t.Errorf("want redacted values in format output, got %q", formatted)
+	}
+	var jsonError struct {
+		Email string `json:"email"`
+		Token string `json:"token"`
+	}
+	if e := json.Unmarshal(jsonData, &jsonError); e != nil {
+		t.Fatalf("failed to unmarshal json: %v", e)
+	}
+	if jsonError.Email != "***" || jsonError.Token != "***" {
+		t.Errorf("want redacted values in JSON, got %s", jsonData)
+	}
+	logger := errorstest.NewLogger()
+	errors.Log(err, logger)
+	logger.AssertField(t, "email", "***")
+
+	if value, _ := errors.GetField(err, "email"); value != "user@example.com" {
+		t.Errorf(`want GetField to return raw value, got %v`, value)
+	}
+}
+
+func TestSetRedactionMask(t *testing.T) {
+	defer errors.SetRedactionMask("***")
+	errors.SetRedactionMask("[hidden]")
+
+	err := errors.Errorf("access denied", errors.Redacted("email", "user@example.com"))
+
+	formatted := fmt.Sprintf("%+v", err)
+	if !strings.Contains(formatted, "email: [hidden]") {
+		t.Errorf("want custom mask in format output, got %q", formatted)
+	}
+}
@@ -9,9 +9,13 @@ import (
 	"runtime"
 	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
 )
 
-type stackTracer interface {
+// StackTracer is implemented by errors that contain a stack trace recorded by this package.
+// It can be used to type-assert an error or as a type parameter of As function.
+type StackTracer interface {
 	StackTrace() StackTrace
 }
 
@@ -24,10 +28,19 @@ type Frame uintptr
 // multiple frames may have the same PC value.
 func (f Frame) pc() uintptr { return uintptr(f) - 1 }
 
+// Func returns the function for this Frame's pc, or nil if the function is unknown
+// (for example, for a zero Frame).
+func (f Frame) Func() *runtime.Func {
+	if f == 0 {
+		return nil
+	}
+	return runtime.FuncForPC(f.pc())
+}
+
 // File returns the full path to the file that contains the
 // function for this Frame's pc.
 func (f Frame) File() string {
-	fn := runtime.FuncForPC(f.pc())
+	fn := f.Func()
 	if fn == nil {
 		return "unknown"
 	}
@@ -38,7 +51,7 @@ func (f Frame) File() string {
 // Line returns the line number of source code of the
 // function for this Frame's pc.
 func (f Frame) Line() int {
-	fn := runtime.FuncForPC(f.pc())
+	fn := f.Func()
 	if fn == nil {
 		return 0
 	}
@@ -48,13 +61,34 @@ func (f Frame) Line() int {
 
 // Name returns the name of this function, if known.
 func (f Frame) Name() string {
-	fn := runtime.FuncForPC(f.pc())
+	fn := f.Func()
 	if fn == nil {
 		return "unknown"
 	}
 	return fn.Name()
 }
 
+// Package returns the import path of the package of this function,
+// or an empty string if the function is unknown.
+func (f Frame) Package() string {
+	name := f.Name()
+	if name == "unknown" {
+		return ""
+	}
+	i := strings.LastIndex(name, "/")
+	j := strings.Index(name[i+1:], ".")
+	if j < 0 {
+		return ""
+	}
+	return name[:i+1+j]
+}
+
+// Short returns the name of this function without the package path,
+// for example "(*T).Method".
+func (f Frame) Short() string {
+	return funcname(f.Name())
+}
+
 // Format formats the frame according to the fmt.Formatter interface.
 //
 //	%s    source file
@@ -129,7 +163,9 @@ type StackTrace []Frame
 //
 // Format accepts flags that alter the printing of some verbs, as follows:
 //
+//	%+s   Prints function and filename for each Frame in the stack.
 //	%+v   Prints filename, function, and line number for each Frame in the stack.
+//	% v   Prints the stack in a single line same as OneLine method.
 func (st StackTrace) Format(s fmt.State, verb rune) {
 	switch verb {
 	case 'v':
@@ -141,10 +177,19 @@ func (st StackTrace) Format(s fmt.State, verb rune) {
 			}
 		case s.Flag('#'):
 			fmt.Fprintf(s, "%#v", []Frame(st))
+		case s.Flag(' '):
+			io.WriteString(s, st.OneLine())
 		default:
 			st.formatSlice(s, verb)
 		}
 	case 's':
+		if s.Flag('+') {
+			for _, f := range st {
+				io.WriteString(s, "\n")
+				f.Format(s, verb)
+			}
+			return
+		}
 		st.formatSlice(s, verb)
 	}
 }
@@ -172,6 +217,50 @@ func (st StackTrace) Strings() []string {
 	return s
 }
 
+// OneLine formats stack trace as a single line text string, that is useful for structured logs.
+// Every Frame is formatted same as by Frame.String and frames are separated by "; ".
+func (st StackTrace) OneLine() string {
+	return strings.Join(st.Strings(), "; ")
+}
+
+// Frames resolves the stack trace into runtime.Frame values by runtime.CallersFrames,
+// that correctly handles inlined calls. It is more expensive than using Frame methods,
+// because all frames are resolved at once.
+func (st StackTrace) Frames() []runtime.Frame {
+	if len(st) == 0 {
+		return nil
+	}
+
+	pcs := make([]uintptr, len(st))
+	for i, frame := range st {
+		pcs[i] = uintptr(frame)
+	}
+
+	frames := make([]runtime.Frame, 0, len(st))
+	callersFrames := runtime.CallersFrames(pcs)
+	for {
+		frame, more := callersFrames.Next()
+		frames = append(frames, frame)
+		if !more {
+			break
+		}
+	}
+
+	return frames
+}
+
+// TrimRuntime returns a copy of the stack trace without trailing frames of the runtime package,
+// like runtime.main and runtime.goexit. The receiver is not modified.
+func (st StackTrace) TrimRuntime() StackTrace {
+	n := len(st)
+	for n > 0 && strings.HasPrefix(st[n-1].Name(), "runtime.") {
+		n--
+	}
+	trimmed := make(StackTrace, n)
+	copy(trimmed, st)
+	return trimmed
+}
+
 // MarshalJSON returns the JSON array representation of every Frame with three fields:
 // function name, file name and line.
 func (st StackTrace) MarshalJSON() ([]byte, error) {
@@ -205,39 +294,225 @@ func (st StackTrace) formatSlice(s fmt.State, verb rune) {
 	io.WriteString(s, "]")
 }
 
+// ResolvedFrame is a stack frame with resolved function name, file and line.
+// Unlike Frame, it does not depend on the process, so it can be used to read
+// a stack trace that was marshaled into JSON earlier.
+type ResolvedFrame struct {
+	Function string `json:"function"`
+	File     string `json:"file,omitempty"`
+	Line     int    `json:"line,omitempty"`
+}
+
+// String formats the frame same as Frame.String does.
+func (f ResolvedFrame) String() string {
+	if f.File == "" {
+		return f.Function
+	}
+	return fmt.Sprintf("%s %s:%d", f.Function, f.File, f.Line)
+}
+
+// ResolvedStackTrace is a stack of resolved frames from innermost (newest) to outermost (oldest).
+type ResolvedStackTrace []ResolvedFrame
+
+// UnmarshalJSON parses the stack trace from the JSON array produced by StackTrace.MarshalJSON.
+func (st *ResolvedStackTrace) UnmarshalJSON(data []byte) error {
+	var frames []ResolvedFrame
+	if err := json.Unmarshal(data, &frames); err != nil {
+		return err
+	}
+	*st = frames
+	return nil
+}
+
+// String formats the stack trace same as StackTrace.String does.
+func (st ResolvedStackTrace) String() string {
+	var s strings.Builder
+	for i, frame := range st {
+		if i > 0 {
+			s.WriteString("\n")
+		}
+		s.WriteString(frame.Function)
+		if frame.File != "" {
+			fmt.Fprintf(&s, "\n\t%s:%d", frame.File, frame.Line)
+		}
+	}
+	return s.String()
+}
+
 // stack represents a stack of program counters.
-type stack []uintptr
+// Program counters are captured eagerly, because it is cheap, but symbols are resolved
+// only on first use and the result is cached.
+type stack struct {
+	pcs       []uintptr
+	topFrames int // set by TopFrames option
+
+	traceOnce sync.Once
+	trace     StackTrace
+
+	formatOnce sync.Once
+	formatted  string
+}
 
 func (s *stack) Format(st fmt.State, verb rune) {
 	switch verb {
 	case 'v':
 		switch {
 		case st.Flag('+'):
-			for _, pc := range *s {
-				f := Frame(pc)
-				fmt.Fprintf(st, "\n%+v", f)
-			}
+			s.formatOnce.Do(func() {
+				var b strings.Builder
+				for _, f := range s.StackTrace() {
+					fmt.Fprintf(&b, "\n%+v", f)
+				}
+				s.formatted = b.String()
+			})
+			io.WriteString(st, s.formatted)
 		}
 	}
 }
 
+// StackTrace returns the stack trace. The result is cached, so it should not be modified.
 func (s *stack) StackTrace() StackTrace {
-	f := make([]Frame, len(*s))
-	for i := 0; i < len(f); i++ {
-		f[i] = Frame((*s)[i])
+	s.traceOnce.Do(func() {
+		s.trace = make([]Frame, len(s.pcs))
+		for i, pc := range s.pcs {
+			s.trace[i] = Frame(pc)
+		}
+	})
+	return s.trace
+}
+
+// GetStackTrace returns the first stack trace found in err's chain, that is the stack trace
+// recorded at the point the error was created or first wrapped by this package.
+// It returns false if the chain does not contain a stack trace.
+func GetStackTrace(err error) (StackTrace, bool) {
+	tracer, ok := As[StackTracer](err)
+	if !ok {
+		return nil, false
+	}
+
+	return tracer.StackTrace(), true
+}
+
+// loggedStackTrace returns the stack trace limited by TopFrames option.
+func (s *stack) loggedStackTrace() StackTrace {
+	trace := s.StackTrace()
+	if s.topFrames > 0 && s.topFrames < len(trace) {
+		return trace[:s.topFrames]
+	}
+	return trace
+}
+
+// limitedStackTrace returns the stack trace to be passed to loggers and marshaled into JSON.
+func limitedStackTrace(tracer StackTracer) StackTrace {
+	if s, ok := tracer.(interface{ loggedStackTrace() StackTrace }); ok {
+		return s.loggedStackTrace()
 	}
-	return f
+	return tracer.StackTrace()
 }
 
-// newStack creates a stack of program counters pointing to the place it was called.
+var defaultStackDepth int32 = 32
+
+// SetDefaultStackDepth sets the maximum number of frames captured in stack traces
+// for the whole process. The initial value is 32. Values less than 1 are treated as 1.
+// The StackDepth option passed to a particular call takes precedence over this value.
+// It is safe to call SetDefaultStackDepth concurrently with creating errors.
+func SetDefaultStackDepth(n int) {
+	if n < 1 {
+		n = 1
+	}
+	atomic.StoreInt32(&defaultStackDepth, int32(n))
+}
+
+// filterCallers removes program counters excluded by the SkipUntil and TrimStackBelow options.
+// The slice is modified in place.
+func (o *Options) filterCallers(pcs []uintptr) []uintptr {
+	for len(pcs) > 0 && hasAnyPrefix(Frame(pcs[0]).Name(), o.skipUntil) {
+		pcs = pcs[1:]
+	}
+
+	return trimCallers(pcs, o.trimmed)
+}
+
+func hasAnyPrefix(s string, prefixes []string) bool {
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(s, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// trimCallers removes program counters of functions from the given packages and their subpackages.
+// The slice is modified in place.
+func trimCallers(pcs []uintptr, packages []string) []uintptr {
+	if len(packages) == 0 {
+		return pcs
+	}
+
+	trimmed := pcs[:0]
+	for _, pc := range pcs {
+		if !isInPackages(Frame(pc).Name(), packages) {
+			trimmed = append(trimmed, pc)
+		}
+	}
+
+	return trimmed
+}
+
+func isInPackages(funcName string, packages []string) bool {
+	for _, pkg := range packages {
+		if strings.HasPrefix(funcName, pkg+".") || strings.HasPrefix(funcName, pkg+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// callers returns program counters of the stack pointing to the place it was called.
 // The argument skip is the number of stack frames to skip before stack trace.
-func newStack(skip int) *stack {
-	const depth = 32
-	var pcs [depth]uintptr
-	n := runtime.Callers(3+skip, pcs[:])
-	var st stack = pcs[0:n]
+// The argument depth is the maximum number of captured frames, if it is not positive
+// then the default depth is used.
+//
+// runtime.Callers expands inlined calls into separate program counters and runtime.FuncForPC
+// resolves such a counter into the innermost inlined function, so every Frame of the stack
+// matches the actual call structure, as it is reported by runtime.CallersFrames.
+//
+// Program counters are captured into a buffer from the pool and only the used part
+// of it is copied into the returned slice.
+func callers(skip, depth int) []uintptr {
+	if depth <= 0 {
+		depth = int(atomic.LoadInt32(&defaultStackDepth))
+	}
+
+	buffer := pcsPool.Get().(*[]uintptr)
+	if cap(*buffer) < depth {
+		*buffer = make([]uintptr, depth)
+	}
+	n := runtime.Callers(3+skip, (*buffer)[:depth])
+
+	pcs := make([]uintptr, n)
+	copy(pcs, (*buffer)[:n])
+	pcsPool.Put(buffer)
+
+	return pcs
+}
+
+var pcsPool = sync.Pool{
+	New: func() interface{} {
+		pcs := make([]uintptr, 0, 32)
+		return &pcs
+	},
+}
+
+// callerName returns the name of the function that called the constructor of an error.
+// It must be called by newOptions, that is called by the constructor itself.
+func callerName(skip int) string {
+	var pcs [1]uintptr
+	if runtime.Callers(4+skip, pcs[:]) == 0 {
+		return "unknown"
+	}
 
-	return &st
+	return Frame(pcs[0]).Name()
 }
 
 // funcname removes the path prefix component of a function's name reported by func.Name().
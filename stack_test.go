@@ -217,7 +217,7 @@ func caller() errors.Frame {
 
 func TestStackTrace_String(t *testing.T) {
 	err := errors.Errorf("ooh")
-	stacked, ok := errors.As[StackTracer](err)
+	stacked, ok := errors.As[errors.StackTracer](err)
 	if !ok {
 		t.Fatalf("expected %#v to implement errors.StackTracer", err)
 	}
@@ -230,7 +230,7 @@ func TestStackTrace_String(t *testing.T) {
 
 func TestStackTrace_Strings(t *testing.T) {
 	err := errors.Errorf("ooh")
-	stacked, ok := errors.As[StackTracer](err)
+	stacked, ok := errors.As[errors.StackTracer](err)
 	if !ok {
 		t.Fatalf("expected %#v to implement errors.StackTracer", err)
 	}
@@ -243,7 +243,7 @@ func TestStackTrace_Strings(t *testing.T) {
 
 func TestStackTrace_MarshalJSON(t *testing.T) {
 	err := errors.Errorf("ooh")
-	stacked, ok := errors.As[StackTracer](err)
+	stacked, ok := errors.As[errors.StackTracer](err)
 	if !ok {
 		t.Fatalf("expected %#v to implement errors.StackTracer", err)
 	}
@@ -266,3 +266,375 @@ func TestStackTrace_MarshalJSON(t *testing.T) {
 		},
 	})
 }
+
+func TestStackDepth(t *testing.T) {
+	const recursionDepth = 50
+
+	tests := []struct {
+		name      string
+		options   []errors.Option
+		wantCalls int
+	}{
+		{name: "default depth", wantCalls: 32},
+		{name: "deep stack", options: []errors.Option{errors.StackDepth(100)}, wantCalls: recursionDepth},
+		{name: "shallow stack", options: []errors.Option{errors.StackDepth(5)}, wantCalls: 5},
+		{name: "depth is clamped to 1", options: []errors.Option{errors.StackDepth(0)}, wantCalls: 1},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := recursiveError(recursionDepth, test.options...)
+
+			tracer, ok := errors.As[errors.StackTracer](err)
+			if !ok {
+				t.Fatal("want error to have a stack trace")
+			}
+			calls := 0
+			for _, frame := range tracer.StackTrace() {
+				if frame.Name() == "github.com/muonsoft/errors_test.recursiveError" {
+					calls++
+				}
+			}
+			if calls != test.wantCalls {
+				t.Errorf("want %d recursive calls in stack trace, got %d", test.wantCalls, calls)
+			}
+		})
+	}
+}
+
+func recursiveError(depth int, options ...errors.Option) error {
+	if depth <= 1 {
+		return errors.Wrap(errors.New("ooh"), options...)
+	}
+	return recursiveError(depth-1, options...)
+}
+
+func TestSetDefaultStackDepth(t *testing.T) {
+	defer errors.SetDefaultStackDepth(32)
+
+	errors.SetDefaultStackDepth(5)
+	err := recursiveError(10)
+	if got := len(stackTraceOf(t, err)); got != 5 {
+		t.Errorf("want 5 frames in stack trace, got %d", got)
+	}
+
+	err = recursiveError(10, errors.StackDepth(7))
+	if got := len(stackTraceOf(t, err)); got != 7 {
+		t.Errorf("want StackDepth option to take precedence: want 7 frames, got %d", got)
+	}
+}
+
+func TestSetDefaultStackDepth_concurrently(t *testing.T) {
+	defer errors.SetDefaultStackDepth(32)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 1; i <= 100; i++ {
+			errors.SetDefaultStackDepth(i)
+		}
+	}()
+	for i := 0; i < 100; i++ {
+		if got := len(stackTraceOf(t, recursiveError(3))); got == 0 {
+			t.Fatal("want stack trace to be captured")
+		}
+	}
+	<-done
+}
+
+func stackTraceOf(t *testing.T, err error) errors.StackTrace {
+	t.Helper()
+	tracer, ok := errors.As[errors.StackTracer](err)
+	if !ok {
+		t.Fatal("want error to have a stack trace")
+	}
+	return tracer.StackTrace()
+}
+
+func TestTrimStackBelow(t *testing.T) {
+	err := errors.Wrap(errors.New("ooh"), errors.TrimStackBelow("testing"), errors.TrimStackBelow("runtime"))
+
+	trace := stackTraceOf(t, err)
+	if len(trace) != 1 {
+		t.Fatalf("want only one frame in stack trace, got %d", len(trace))
+	}
+	if name := trace[0].Name(); name != "github.com/muonsoft/errors_test.TestTrimStackBelow" {
+		t.Errorf("want frame of test function, got %s", name)
+	}
+}
+
+func TestTrimStackBelow_packagePrefix(t *testing.T) {
+	err := errors.Errorf("ooh", errors.TrimStackBelow("test"))
+
+	trace := stackTraceOf(t, err)
+	found := false
+	for _, frame := range trace {
+		if frame.Name() == "testing.tRunner" {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("want frames of other packages with the same prefix to be kept: %v", trace.Strings())
+	}
+}
+
+func TestStackTrace_TrimRuntime(t *testing.T) {
+	trace := stackTraceOf(t, errors.Wrap(errors.New("ooh")))
+	goexit := trace[len(trace)-1]
+	if goexit.Name() != "runtime.goexit" {
+		t.Fatalf("want last frame to be runtime.goexit, got %s", goexit.Name())
+	}
+	user := caller()
+
+	st := errors.StackTrace{user, goexit, user, goexit, goexit}
+	got := st.TrimRuntime()
+
+	want := errors.StackTrace{user, goexit, user}
+	if len(got) != len(want) {
+		t.Fatalf("want %d frames, got %d: %v", len(want), len(got), got.Strings())
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("want frame #%d to be %v, got %v", i, want[i], got[i])
+		}
+	}
+	if len(st) != 5 || st[4] != goexit {
+		t.Errorf("want receiver to be unmodified, got %v", st.Strings())
+	}
+}
+
+func TestFrame_Package(t *testing.T) {
+	tests := []struct {
+		name  string
+		frame errors.Frame
+		want  string
+	}{
+		{"init", initpc, "github.com/muonsoft/errors_test"},
+		{"method", X{}.val(), "github.com/muonsoft/errors_test"},
+		{"unknown", 0, ""},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := test.frame.Package(); got != test.want {
+				t.Errorf("want package %q, got %q", test.want, got)
+			}
+		})
+	}
+}
+
+func TestFrame_Short(t *testing.T) {
+	tests := []struct {
+		name  string
+		frame errors.Frame
+		want  string
+	}{
+		{"init", initpc, "init"},
+		{"value method", X{}.val(), "X.val"},
+		{"pointer method", (&X{}).ptr(), "(*X).ptr"},
+		{"unknown", 0, "unknown"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := test.frame.Short(); got != test.want {
+				t.Errorf("want short name %q, got %q", test.want, got)
+			}
+		})
+	}
+}
+
+func TestStackTrace_Frames(t *testing.T) {
+	trace := stackTraceOf(t, errors.Wrap(errors.New("ooh")))
+
+	frames := trace.Frames()
+
+	if len(frames) != len(trace) {
+		t.Fatalf("want %d frames, got %d", len(trace), len(frames))
+	}
+	for i, frame := range frames {
+		if frame.Function != trace[i].Name() {
+			t.Errorf("want frame #%d function to be %s, got %s", i, trace[i].Name(), frame.Function)
+		}
+		if frame.Line != trace[i].Line() {
+			t.Errorf("want frame #%d line to be %d, got %d", i, trace[i].Line(), frame.Line)
+		}
+	}
+	if frames[0].Func == nil {
+		t.Error("want first frame to have a function")
+	}
+}
+
+func TestStackTrace_inlinedFrames(t *testing.T) {
+	trace := stackTraceOf(t, notInlined())
+
+	want := []string{
+		"github.com/muonsoft/errors_test.inlinable",
+		"github.com/muonsoft/errors_test.notInlined",
+		"github.com/muonsoft/errors_test.TestStackTrace_inlinedFrames",
+	}
+	if len(trace) < len(want) {
+		t.Fatalf("want at least %d frames, got %d", len(want), len(trace))
+	}
+	for i, name := range want {
+		if trace[i].Name() != name {
+			t.Errorf("want frame #%d to be %s, got %s", i, name, trace[i].Name())
+		}
+	}
+	frames := trace.Frames()
+	for i := range want {
+		if frames[i].Function != trace[i].Name() || frames[i].Line != trace[i].Line() {
+			t.Errorf("want frame #%d to match runtime frame %s:%d, got %s:%d",
+				i, frames[i].Function, frames[i].Line, trace[i].Name(), trace[i].Line())
+		}
+	}
+}
+
+// inlinable is small enough to be inlined into notInlined.
+func inlinable() error {
+	return errors.Wrap(errTest)
+}
+
+//go:noinline
+func notInlined() error {
+	return inlinable()
+}
+
+func TestResolvedStackTrace_UnmarshalJSON(t *testing.T) {
+	trace := stackTraceOf(t, errors.Wrap(errors.New("ooh")))
+	data, err := json.Marshal(trace)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var resolved errors.ResolvedStackTrace
+	err = json.Unmarshal(data, &resolved)
+
+	if err != nil {
+		t.Fatalf("failed to unmarshal stack trace: %v", err)
+	}
+	if len(resolved) != len(trace) {
+		t.Fatalf("want %d frames, got %d", len(trace), len(resolved))
+	}
+	for i, frame := range trace {
+		if resolved[i].Function != frame.Name() || resolved[i].File != frame.File() || resolved[i].Line != frame.Line() {
+			t.Errorf("want frame #%d to be %s, got %s", i, frame.String(), resolved[i].String())
+		}
+	}
+	if resolved.String() != trace.String() {
+		t.Errorf("want resolved stack trace to be formatted as\n%s\ngot\n%s", trace.String(), resolved.String())
+	}
+}
+
+func TestStackTrace_independentAcrossGoroutines(t *testing.T) {
+	const count = 50
+	results := make(chan [2]error, count)
+	for i := 0; i < count; i++ {
+		go func() {
+			results <- [2]error{firstCallSite(), secondCallSite()}
+		}()
+	}
+
+	for i := 0; i < count; i++ {
+		errs := <-results
+		traces := [2]errors.StackTrace{stackTraceOf(t, errs[0]), stackTraceOf(t, errs[1])}
+		if name := traces[0][0].Name(); name != "github.com/muonsoft/errors_test.firstCallSite" {
+			t.Errorf("want first trace to start at firstCallSite, got %s", name)
+		}
+		if name := traces[1][0].Name(); name != "github.com/muonsoft/errors_test.secondCallSite" {
+			t.Errorf("want second trace to start at secondCallSite, got %s", name)
+		}
+	}
+}
+
+//go:noinline
+func firstCallSite() error { return errors.Errorf("first") }
+
+//go:noinline
+func secondCallSite() error { return errors.Errorf("second") }
+
+func TestSkipUntil(t *testing.T) {
+	wrapHelper := func(err error) error {
+		return errors.Wrap(err, errors.SkipUntil("github.com/muonsoft/errors_test.TestSkipUntil.func"))
+	}
+
+	err := wrapHelper(errors.New("ooh"))
+
+	trace := stackTraceOf(t, err)
+	if name := trace[0].Name(); name != "github.com/muonsoft/errors_test.TestSkipUntil" {
+		t.Errorf("want stack trace to start at the real caller, got %s", name)
+	}
+}
+
+func TestSkipUntil_noMatch(t *testing.T) {
+	err := errors.Errorf("ooh", errors.SkipUntil("github.com/muonsoft/errors_test.unknown"))
+
+	trace := stackTraceOf(t, err)
+	if name := trace[0].Name(); name != "github.com/muonsoft/errors_test.TestSkipUntil_noMatch" {
+		t.Errorf("want stack trace to be unchanged, got %s", name)
+	}
+}
+
+func TestStackTrace_Format_plusS(t *testing.T) {
+	tests := []struct {
+		errors.StackTrace
+		want string
+	}{
+		{nil, ""},
+		{make(errors.StackTrace, 0), ""},
+		{
+			stackTrace()[:2],
+			"\n" +
+				"github.com/muonsoft/errors_test.stackTrace\n" +
+				"\t.+/errors/stack_test.go\n" +
+				"github.com/muonsoft/errors_test.TestStackTrace_Format_plusS\n" +
+				"\t.+/errors/stack_test.go$",
+		},
+	}
+	for _, test := range tests {
+		assertFormatRegexp(t, test.StackTrace, "%+s", test.want)
+	}
+}
+
+func TestGetStackTrace(t *testing.T) {
+	err := errors.Wrapf(errors.Errorf("ooh"), "wrapped", errors.String("key", "value"))
+
+	trace, ok := errors.GetStackTrace(err)
+
+	if !ok {
+		t.Fatalf("want %#v to have a stack trace", err)
+	}
+	if name := trace[0].Name(); name != "github.com/muonsoft/errors_test.TestGetStackTrace" {
+		t.Errorf("want stack trace to start at the test function, got %s", name)
+	}
+}
+
+func TestGetStackTrace_noStack(t *testing.T) {
+	for _, err := range []error{nil, errors.New("ooh")} {
+		if trace, ok := errors.GetStackTrace(err); ok || trace != nil {
+			t.Errorf("want no stack trace for %#v, got %v", err, trace)
+		}
+	}
+}
+
+func TestStackTracer(t *testing.T) {
+	for _, err := range []error{errors.Errorf("ooh"), errors.Wrap(errors.New("ooh")), errors.WithStack(errors.New("ooh"))} {
+		if _, ok := err.(errors.StackTracer); !ok {
+			t.Errorf("want %#v to implement errors.StackTracer", err)
+		}
+	}
+}
+
+func TestFrame_Func(t *testing.T) {
+	trace, _ := errors.GetStackTrace(errors.Errorf("ooh"))
+	frame := trace[0]
+
+	fn := frame.Func()
+
+	if fn == nil {
+		t.Fatal("want function of the frame")
+	}
+	if fn.Name() != frame.Name() {
+		t.Errorf("want function name %s, got %s", frame.Name(), fn.Name())
+	}
+	if errors.Frame(0).Func() != nil {
+		t.Error("want nil function for zero frame")
+	}
+}
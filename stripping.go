@@ -0,0 +1,111 @@
+package errors
+
+import "reflect"
+
+// StripStack returns an error equivalent to err, but without stack traces recorded by this package.
+// Messages and fields are preserved, and errors of the chain still match by Is and As functions.
+// It can be used to log expected errors (like validation errors of user input) without the noise
+// of stack traces. The input error is not modified: wrappers containing stack traces are rebuilt.
+// Stack traces of other packages are not removed. If err is nil, StripStack returns nil.
+func StripStack(err error) error {
+	stripped, _ := stripStackAt(err, 0, &unwrapWalk{})
+
+	return stripped
+}
+
+// stripStackAt rebuilds the chain of err without stack traces and reports whether
+// anything was stripped. Errors without stack traces in their chain are returned as is.
+func stripStackAt(err error, depth int, walk *unwrapWalk) (error, bool) {
+	if err == nil || !walk.next(depth) {
+		return err, false
+	}
+
+	switch e := err.(type) {
+	case *stacked:
+		inner, _ := stripStackAt(e.wrapped.wrapped, depth+1, walk)
+		return &wrapped{wrapped: inner, fields: e.fields, marker: e.marker}, true
+	case *wrapped:
+		if inner, ok := stripStackAt(e.wrapped, depth+1, walk); ok {
+			return &wrapped{wrapped: inner, fields: e.fields, marker: e.marker}, true
+		}
+	case *joinError:
+		if errs, ok := stripStacks(e, e.errs, depth+1, walk); ok {
+			return &joinError{errs: errs, separator: e.separator, mergeStrategy: e.mergeStrategy}, true
+		}
+	case interface{ Unwrap() error }:
+		if inner, ok := stripStackAt(e.Unwrap(), depth+1, walk); ok {
+			return &unstacked{foreign: foreign{err: err}, wrapped: inner}, true
+		}
+	case interface{ Unwrap() []error }:
+		if errs, ok := stripStacks(err, e.Unwrap(), depth+1, walk); ok {
+			return &unstackedJoin{foreign: foreign{err: err}, errs: errs}, true
+		}
+	}
+
+	return err, false
+}
+
+func stripStacks(joined error, errs []error, depth int, walk *unwrapWalk) ([]error, bool) {
+	if !walk.enter(joined) {
+		return nil, false
+	}
+	isStripped := false
+	stripped := make([]error, len(errs))
+	for i, err := range errs {
+		var ok bool
+		stripped[i], ok = stripStackAt(err, depth, walk)
+		isStripped = isStripped || ok
+	}
+
+	return stripped, isStripped
+}
+
+// foreign keeps an error of another package, which was rebuilt by StripStack,
+// so that it still matches by Is and As functions and keeps its fields.
+type foreign struct {
+	err error
+}
+
+func (f foreign) Error() string { return f.err.Error() }
+
+func (f foreign) Is(target error) bool {
+	if reflect.TypeOf(f.err).Comparable() && f.err == target {
+		return true
+	}
+	if x, ok := f.err.(interface{ Is(error) bool }); ok {
+		return x.Is(target)
+	}
+	return false
+}
+
+func (f foreign) As(target any) bool {
+	value := reflect.ValueOf(target)
+	if value.Kind() == reflect.Ptr && !value.IsNil() && reflect.TypeOf(f.err).AssignableTo(value.Type().Elem()) {
+		value.Elem().Set(reflect.ValueOf(f.err))
+		return true
+	}
+	if x, ok := f.err.(interface{ As(any) bool }); ok {
+		return x.As(target)
+	}
+	return false
+}
+
+func (f foreign) LogFields(logger FieldLogger) {
+	if loggable, ok := f.err.(LoggableError); ok {
+		loggable.LogFields(logger)
+	}
+}
+
+type unstacked struct {
+	foreign
+	wrapped error
+}
+
+func (e *unstacked) Unwrap() error { return e.wrapped }
+
+type unstackedJoin struct {
+	foreign
+	errs []error
+}
+
+func (e *unstackedJoin) Unwrap() []error { return e.errs }
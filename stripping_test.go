@@ -0,0 +1,68 @@
+package errors_test
+
+import (
+	"fmt"
+	"io/fs"
+	"testing"
+
+	"github.com/muonsoft/errors"
+	"github.com/muonsoft/errors/errorstest"
+)
+
+func TestStripStack(t *testing.T) {
+	sentinel := errors.New("sentinel")
+	pathError := &fs.PathError{Op: "open", Path: "file.txt", Err: errors.Wrap(sentinel)}
+	err := errors.Wrap(
+		errors.Join(
+			fmt.Errorf("foreign: %w", errors.Errorf("inner: %w", pathError, errors.String("key", "value"))),
+			errors.Errorf("second"),
+		),
+		errors.Int("id", 1),
+	)
+
+	stripped := errors.StripStack(err)
+
+	if _, ok := errors.As[errors.StackTracer](stripped); ok {
+		t.Errorf("want stripped error to have no stack trace")
+	}
+	if _, ok := errors.As[errors.StackTracer](err); !ok {
+		t.Errorf("want original error not to be modified")
+	}
+	if stripped.Error() != err.Error() {
+		t.Errorf("want message %q, got %q", err.Error(), stripped.Error())
+	}
+	if !errors.Is(stripped, sentinel) {
+		t.Errorf("want stripped error to match the sentinel")
+	}
+	if e, ok := errors.As[*fs.PathError](stripped); !ok || e != pathError {
+		t.Errorf("want stripped error to match the original *fs.PathError, got %#v", e)
+	}
+	logger := errorstest.NewLogger()
+	errors.Log(stripped, logger)
+	logger.AssertField(t, "key", "value")
+	logger.AssertField(t, "id", 1)
+	if logger.StackTrace != nil {
+		t.Errorf("want no stack trace in log")
+	}
+}
+
+func TestStripStack_withoutStack(t *testing.T) {
+	err := errors.New("ooh")
+
+	if stripped := errors.StripStack(err); stripped != err {
+		t.Errorf("want error without stack to be returned as is, got %#v", stripped)
+	}
+	if stripped := errors.StripStack(nil); stripped != nil {
+		t.Errorf("want nil, got %#v", stripped)
+	}
+}
+
+func TestStripStack_keepsMergeStrategy(t *testing.T) {
+	err := errors.JoinInto(errors.NumberedSuffix, errors.Errorf("first"), errors.Errorf("second"))
+
+	stripped := errors.StripStack(err)
+
+	if strategy, ok := errors.GetMergeStrategy(stripped); !ok || strategy != errors.NumberedSuffix {
+		t.Errorf("want merge strategy %d, got %d", errors.NumberedSuffix, strategy)
+	}
+}
@@ -0,0 +1,64 @@
+package errors
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+const violationsKey = "violations"
+
+// FieldError describes a violation of a single field, for example, a field of a submitted form.
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+func (e FieldError) String() string {
+	return e.Field + ": " + e.Message
+}
+
+// FieldErrors is an error that collects violations of several fields, as it is usual for validation.
+// Violations are logged and marshaled into JSON as a "violations" field with a list of FieldError.
+// FieldErrors can be wrapped by Wrap or Errorf as any other error to record a stack trace.
+type FieldErrors struct {
+	violations []FieldError
+}
+
+// NewFieldErrors returns an empty list of violations. Use Add method to fill it
+// and Len method to check whether any violation is found.
+func NewFieldErrors() *FieldErrors {
+	return &FieldErrors{}
+}
+
+// Add appends a violation of the field with the message.
+func (e *FieldErrors) Add(field, message string) {
+	e.violations = append(e.violations, FieldError{Field: field, Message: message})
+}
+
+// Len returns the number of violations.
+func (e *FieldErrors) Len() int {
+	return len(e.violations)
+}
+
+// Violations returns the list of violations in the order they are added.
+func (e *FieldErrors) Violations() []FieldError {
+	return e.violations
+}
+
+// Error returns violations joined by "; ", for example "name: is required; email: is invalid".
+func (e *FieldErrors) Error() string {
+	messages := make([]string, len(e.violations))
+	for i, violation := range e.violations {
+		messages[i] = violation.String()
+	}
+
+	return strings.Join(messages, "; ")
+}
+
+func (e *FieldErrors) LogFields(logger FieldLogger) {
+	logger.SetValue(violationsKey, e.violations)
+}
+
+func (e *FieldErrors) MarshalJSON() ([]byte, error) {
+	return json.Marshal(errorData(e, defaultMarshalConfig))
+}
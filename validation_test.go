@@ -0,0 +1,70 @@
+package errors_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/muonsoft/errors"
+	"github.com/muonsoft/errors/errorstest"
+)
+
+func newViolations() *errors.FieldErrors {
+	violations := errors.NewFieldErrors()
+	violations.Add("name", "is required")
+	violations.Add("email", "is invalid")
+
+	return violations
+}
+
+func TestFieldErrors_Error(t *testing.T) {
+	violations := newViolations()
+
+	if violations.Len() != 2 {
+		t.Errorf("want 2 violations, got %d", violations.Len())
+	}
+	if want := "name: is required; email: is invalid"; violations.Error() != want {
+		t.Errorf(`want message "%s", got "%s"`, want, violations.Error())
+	}
+}
+
+func TestFieldErrors_Log(t *testing.T) {
+	violations := newViolations()
+	err := errors.Wrap(violations, errors.String("form", "signup"))
+
+	logger := errorstest.NewLogger()
+	errors.Log(err, logger)
+
+	logger.AssertMessage(t, "name: is required; email: is invalid")
+	logger.AssertField(t, "form", "signup")
+	logger.AssertField(t, "violations", []errors.FieldError{
+		{Field: "name", Message: "is required"},
+		{Field: "email", Message: "is invalid"},
+	})
+	assertSingleStack(t, err)
+	if !errors.Is(err, violations) {
+		t.Errorf("want %#v to wrap violations", err)
+	}
+}
+
+func TestFieldErrors_MarshalJSON(t *testing.T) {
+	for _, err := range []error{newViolations(), errors.Wrap(newViolations())} {
+		data, e := json.Marshal(err)
+		if e != nil {
+			t.Fatalf("want %#v to be marshalable into json: %v", err, e)
+		}
+		var got struct {
+			Error      string              `json:"error"`
+			Violations []errors.FieldError `json:"violations"`
+		}
+		if e := json.Unmarshal(data, &got); e != nil {
+			t.Fatalf("failed to unmarshal json: %v", e)
+		}
+
+		if got.Error != "name: is required; email: is invalid" {
+			t.Errorf(`want error "name: is required; email: is invalid", got "%s"`, got.Error)
+		}
+		if len(got.Violations) != 2 || got.Violations[0].Field != "name" || got.Violations[1].Message != "is invalid" {
+			t.Errorf("want two violations in %s", data)
+		}
+	}
+}